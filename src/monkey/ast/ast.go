@@ -2,6 +2,7 @@ package ast
 
 import (
 	"bytes"
+	"math/big"
 	"monkey/token"
 	"strings"
 )
@@ -58,9 +59,10 @@ func (es *ExpressionStatement) String() string {
 }
 
 type LetStatement struct {
-	Token token.Token // corresponds to token.LET
-	Name  *Identifier
-	Value Expression
+	Token   token.Token // corresponds to token.LET
+	Name    *Identifier
+	Pattern Pattern // set instead of Name when the value is destructured
+	Value   Expression
 }
 
 func (ls *LetStatement) statementNode()       {}
@@ -69,7 +71,11 @@ func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
@@ -118,6 +124,60 @@ func (il *IntegerLiteral) expressionNode()      {}
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
 func (il *IntegerLiteral) String() string       { return il.Token.Literal }
 
+// An integer literal too large to be represented by an int64
+type BigIntegerLiteral struct {
+	Token token.Token
+	Value *big.Int
+}
+
+func (bl *BigIntegerLiteral) expressionNode()      {}
+func (bl *BigIntegerLiteral) TokenLiteral() string { return bl.Token.Literal }
+func (bl *BigIntegerLiteral) String() string       { return bl.Token.Literal }
+
+// A decimal literal such as 12.50d, stored as the unscaled value and the number of
+// digits after the decimal point
+type DecimalLiteral struct {
+	Token token.Token
+	Value *big.Int
+	Scale int
+}
+
+func (dl *DecimalLiteral) expressionNode()      {}
+func (dl *DecimalLiteral) TokenLiteral() string { return dl.Token.Literal }
+func (dl *DecimalLiteral) String() string       { return dl.Token.Literal }
+
+type StringLiteral struct {
+	Token token.Token
+	Value string
+}
+
+func (sl *StringLiteral) expressionNode()      {}
+func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
+func (sl *StringLiteral) String() string       { return sl.Token.Literal }
+
+// A string with interpolations: "${n} items". Parts alternates between StringLiterals
+// for the text and the interpolated expressions.
+type TemplateLiteral struct {
+	Token token.Token // the TEMPLATE token
+	Parts []Expression
+}
+
+func (tl *TemplateLiteral) expressionNode()      {}
+func (tl *TemplateLiteral) TokenLiteral() string { return tl.Token.Literal }
+func (tl *TemplateLiteral) String() string {
+	var out bytes.Buffer
+
+	for _, part := range tl.Parts {
+		if text, ok := part.(*StringLiteral); ok {
+			out.WriteString(text.Value)
+		} else {
+			out.WriteString("${" + part.String() + "}")
+		}
+	}
+
+	return out.String()
+}
+
 type Boolean struct {
 	Token token.Token
 	Value bool
@@ -129,8 +189,13 @@ func (b *Boolean) String() string       { return b.Token.Literal }
 
 type FunctionLiteral struct {
 	Token      token.Token
+	Name       string // set for methods declared in a class
 	Parameters []*Identifier
+	Patterns   []Pattern    // destructuring pattern of each parameter, nil if none destructure
+	Defaults   []Expression // default value of each parameter, nil if none have one
+	Rest       *Identifier  // collects surplus arguments, nil if the function has none
 	Body       *BlockStatement
+	Generator  bool // declared with fn*, so calling it returns a generator
 }
 
 func (fl *FunctionLiteral) expressionNode()      {}
@@ -138,20 +203,38 @@ func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 
-	params := []string{}
-	for _, p := range fl.Parameters {
-		params = append(params, p.String())
-	}
-
 	out.WriteString(fl.TokenLiteral())
+	if fl.Generator {
+		out.WriteString("*")
+	}
+	if fl.Name != "" {
+		out.WriteString(" " + fl.Name)
+	}
 	out.WriteString("(")
-	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(FormatParameters(fl.Parameters, fl.Defaults, fl.Rest))
 	out.WriteString(")")
 	out.WriteString(fl.Body.String())
 
 	return out.String()
 }
 
+// Writes out a parameter list along with its default values and rest parameter
+func FormatParameters(params []*Identifier, defaults []Expression, rest *Identifier) string {
+	out := []string{}
+	for i, p := range params {
+		if defaults != nil && defaults[i] != nil {
+			out = append(out, p.String()+" = "+defaults[i].String())
+		} else {
+			out = append(out, p.String())
+		}
+	}
+	if rest != nil {
+		out = append(out, "..."+rest.String())
+	}
+
+	return strings.Join(out, ", ")
+}
+
 type PrefixExpression struct {
 	Token    token.Token
 	Operator string
@@ -219,10 +302,30 @@ func (ie *IfExpression) String() string {
 	return out.String()
 }
 
+type WhileExpression struct {
+	Token     token.Token // the while token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (we *WhileExpression) expressionNode()      {}
+func (we *WhileExpression) TokenLiteral() string { return we.Token.Literal }
+func (we *WhileExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while")
+	out.WriteString(we.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(we.Body.String())
+
+	return out.String()
+}
+
 type CallExpression struct {
 	Token     token.Token // the ( token
 	Function  Expression  // Identifier or FunctionLiteral
 	Arguments []Expression
+	Optional  bool // f?.(x), which is null without a call if f is null
 }
 
 func (ce *CallExpression) expressionNode()      {}
@@ -236,6 +339,9 @@ func (ce *CallExpression) String() string {
 	}
 
 	out.WriteString(ce.Function.String())
+	if ce.Optional {
+		out.WriteString("?.")
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(args, ", "))
 	out.WriteString(")")
@@ -259,3 +365,501 @@ func (bs *BlockStatement) String() string {
 
 	return out.String()
 }
+
+type ArrayLiteral struct {
+	Token    token.Token // the [ token
+	Elements []Expression
+}
+
+func (al *ArrayLiteral) expressionNode()      {}
+func (al *ArrayLiteral) TokenLiteral() string { return al.Token.Literal }
+func (al *ArrayLiteral) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, el := range al.Elements {
+		elements = append(elements, el.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+type IndexExpression struct {
+	Token    token.Token // the [ token
+	Left     Expression
+	Index    Expression
+	Optional bool // a?[i], which is null if a is
+}
+
+func (ie *IndexExpression) expressionNode()      {}
+func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IndexExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ie.Left.String())
+	if ie.Optional {
+		out.WriteString("?")
+	}
+	out.WriteString("[")
+	out.WriteString(ie.Index.String())
+	out.WriteString("])")
+
+	return out.String()
+}
+
+type HashLiteral struct {
+	Token token.Token // the { token
+	Keys  []Expression
+	Pairs map[Expression]Expression
+}
+
+func (hl *HashLiteral) expressionNode()      {}
+func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
+func (hl *HashLiteral) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, key := range hl.Keys {
+		pairs = append(pairs, key.String()+":"+hl.Pairs[key].String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Access to a member of an object, such as a hash key or a method: object.member
+type MemberExpression struct {
+	Token    token.Token // the . token
+	Object   Expression
+	Member   *Identifier
+	Optional bool // a?.b, which is null if a is
+}
+
+func (me *MemberExpression) expressionNode()      {}
+func (me *MemberExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MemberExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(me.Object.String())
+	if me.Optional {
+		out.WriteString("?")
+	}
+	out.WriteString(".")
+	out.WriteString(me.Member.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// Declares a named record type: struct Point { x, y }
+type StructStatement struct {
+	Token  token.Token // the struct token
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (ss *StructStatement) statementNode()       {}
+func (ss *StructStatement) TokenLiteral() string { return ss.Token.Literal }
+func (ss *StructStatement) String() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, f := range ss.Fields {
+		fields = append(fields, f.String())
+	}
+
+	out.WriteString("struct ")
+	out.WriteString(ss.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// Constructs a struct with named fields: Point{x: 1, y: 2}
+type StructLiteral struct {
+	Token  token.Token // the name of the struct
+	Name   *Identifier
+	Fields []*Identifier
+	Values []Expression
+}
+
+func (sl *StructLiteral) expressionNode()      {}
+func (sl *StructLiteral) TokenLiteral() string { return sl.Token.Literal }
+func (sl *StructLiteral) String() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for i, f := range sl.Fields {
+		fields = append(fields, f.String()+": "+sl.Values[i].String())
+	}
+
+	out.WriteString(sl.Name.String())
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Assigns a new value to a member of an object: point.x = 3
+type AssignExpression struct {
+	Token  token.Token // the = token
+	Target *MemberExpression
+	Value  Expression
+}
+
+func (ae *AssignExpression) expressionNode()      {}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ae.Target.String())
+	out.WriteString(" = ")
+	out.WriteString(ae.Value.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// Declares a class of objects sharing methods: class Loud < Counter { fn inc(self) { ... } }
+type ClassStatement struct {
+	Token   token.Token // the class token
+	Name    *Identifier
+	Parent  *Identifier // nil when the class does not inherit
+	Methods []*FunctionLiteral
+}
+
+func (cs *ClassStatement) statementNode()       {}
+func (cs *ClassStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ClassStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("class ")
+	out.WriteString(cs.Name.String())
+	if cs.Parent != nil {
+		out.WriteString(" < ")
+		out.WriteString(cs.Parent.String())
+	}
+	out.WriteString(" { ")
+	for _, m := range cs.Methods {
+		out.WriteString(m.String())
+		out.WriteString(" ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Declares a set of tagged variants: enum Shape { Circle(r), Rect(w, h), Empty }
+type EnumStatement struct {
+	Token    token.Token // the enum token
+	Name     *Identifier
+	Variants []*EnumVariant
+}
+
+type EnumVariant struct {
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (es *EnumStatement) statementNode()       {}
+func (es *EnumStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *EnumStatement) String() string {
+	var out bytes.Buffer
+
+	variants := []string{}
+	for _, v := range es.Variants {
+		variant := v.Name.String()
+		if len(v.Fields) > 0 {
+			fields := []string{}
+			for _, f := range v.Fields {
+				fields = append(fields, f.String())
+			}
+			variant += "(" + strings.Join(fields, ", ") + ")"
+		}
+		variants = append(variants, variant)
+	}
+
+	out.WriteString("enum ")
+	out.WriteString(es.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(variants, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// Evaluates the body of the first arm whose pattern and guard match the subject
+type MatchExpression struct {
+	Token   token.Token // the match token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expression // nil when the arm is unguarded
+	Body    *BlockStatement
+}
+
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("match ")
+	out.WriteString(me.Subject.String())
+	out.WriteString(" { ")
+	for _, arm := range me.Arms {
+		out.WriteString(arm.Pattern.String())
+		if arm.Guard != nil {
+			out.WriteString(" if ")
+			out.WriteString(arm.Guard.String())
+		}
+		out.WriteString(" -> ")
+		out.WriteString(arm.Body.String())
+		out.WriteString("; ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Expands an array into separate arguments or elements: f(...args)
+type SpreadExpression struct {
+	Token token.Token // the ... token
+	Value Expression
+}
+
+func (se *SpreadExpression) expressionNode()      {}
+func (se *SpreadExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpreadExpression) String() string       { return "..." + se.Value.String() }
+
+// Passes an argument to the parameter of the same name: f(y: 2)
+type NamedArgument struct {
+	Token token.Token // the name of the parameter
+	Name  *Identifier
+	Value Expression
+}
+
+func (na *NamedArgument) expressionNode()      {}
+func (na *NamedArgument) TokenLiteral() string { return na.Token.Literal }
+func (na *NamedArgument) String() string       { return na.Name.String() + ": " + na.Value.String() }
+
+// Passes the left value as the first argument of the right call: x |> f(a) is f(x, a)
+type PipeExpression struct {
+	Token token.Token // the |> token
+	Left  Expression
+	Right Expression
+}
+
+func (pe *PipeExpression) expressionNode()      {}
+func (pe *PipeExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PipeExpression) String() string {
+	return "(" + pe.Left.String() + " |> " + pe.Right.String() + ")"
+}
+
+// A range of integers, either of whose bounds may be left open: 1..10, 1..=10 step 2, ..5
+type RangeExpression struct {
+	Token     token.Token // the .. or ..= token
+	Start     Expression  // nil when open at the start
+	End       Expression  // nil when open at the end
+	Step      Expression  // nil for a step of 1
+	Inclusive bool
+}
+
+func (re *RangeExpression) expressionNode()      {}
+func (re *RangeExpression) TokenLiteral() string { return re.Token.Literal }
+func (re *RangeExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	if re.Start != nil {
+		out.WriteString(re.Start.String())
+	}
+	out.WriteString(re.Token.Literal)
+	if re.End != nil {
+		out.WriteString(re.End.String())
+	}
+	if re.Step != nil {
+		out.WriteString(" step ")
+		out.WriteString(re.Step.String())
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// Runs the body once for each element of a sequence: for (x in xs) { ... }
+type ForExpression struct {
+	Token    token.Token // the for token
+	Pattern  Pattern
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fe *ForExpression) expressionNode()      {}
+func (fe *ForExpression) TokenLiteral() string { return fe.Token.Literal }
+func (fe *ForExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	out.WriteString(fe.Pattern.String())
+	out.WriteString(" in ")
+	out.WriteString(fe.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fe.Body.String())
+
+	return out.String()
+}
+
+// Hands a value to whoever is iterating the enclosing generator: yield x
+type YieldExpression struct {
+	Token token.Token // the yield token
+	Value Expression  // nil for a bare yield, which yields null
+}
+
+func (ye *YieldExpression) expressionNode()      {}
+func (ye *YieldExpression) TokenLiteral() string { return ye.Token.Literal }
+func (ye *YieldExpression) String() string {
+	if ye.Value == nil {
+		return "yield"
+	}
+	return "yield " + ye.Value.String()
+}
+
+// Runs Body and hands an error raised in it to the catch block, then runs the finally
+// block either way: try { ... } catch (e) { ... } finally { ... }
+type TryExpression struct {
+	Token     token.Token // the try token
+	Body      *BlockStatement
+	Parameter *Identifier     // binds the caught error, nil if the catch block takes none
+	Catch     *BlockStatement // nil if there is no catch block
+	Finally   *BlockStatement // nil if there is no finally block
+}
+
+func (te *TryExpression) expressionNode()      {}
+func (te *TryExpression) TokenLiteral() string { return te.Token.Literal }
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(te.Body.String())
+
+	if te.Catch != nil {
+		out.WriteString(" catch ")
+		if te.Parameter != nil {
+			out.WriteString("(" + te.Parameter.String() + ") ")
+		}
+		out.WriteString(te.Catch.String())
+	}
+
+	if te.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(te.Finally.String())
+	}
+
+	return out.String()
+}
+
+// Raises an error: throw "bad record"
+type ThrowExpression struct {
+	Token token.Token // the throw token
+	Value Expression
+}
+
+func (te *ThrowExpression) expressionNode()      {}
+func (te *ThrowExpression) TokenLiteral() string { return te.Token.Literal }
+func (te *ThrowExpression) String() string       { return "throw " + te.Value.String() }
+
+// Unwraps an ok result, or returns an err result from the enclosing function: f(x)?
+type PropagateExpression struct {
+	Token token.Token // the ? token
+	Value Expression
+}
+
+func (pe *PropagateExpression) expressionNode()      {}
+func (pe *PropagateExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PropagateExpression) String() string       { return "(" + pe.Value.String() + "?)" }
+
+// Falls back to Right when Left is null: a ?? b
+type CoalesceExpression struct {
+	Token token.Token // the ?? token
+	Left  Expression
+	Right Expression
+}
+
+func (ce *CoalesceExpression) expressionNode()      {}
+func (ce *CoalesceExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CoalesceExpression) String() string {
+	return "(" + ce.Left.String() + " ?? " + ce.Right.String() + ")"
+}
+
+// Picks one of two values: cond ? a : b
+type ConditionalExpression struct {
+	Token       token.Token // the ? token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (ce *ConditionalExpression) expressionNode()      {}
+func (ce *ConditionalExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *ConditionalExpression) String() string {
+	return "(" + ce.Condition.String() + " ? " + ce.Consequence.String() + " : " + ce.Alternative.String() + ")"
+}
+
+// Runs the body of the first case listing a value equal to the subject:
+// switch (x) { case 1, 2 -> a; default -> b }
+type SwitchExpression struct {
+	Token   token.Token // the switch token
+	Subject Expression
+	Cases   []*SwitchCase
+	Default *BlockStatement // nil if there is no default case
+}
+
+type SwitchCase struct {
+	Values []Expression
+	Body   *BlockStatement
+}
+
+func (se *SwitchExpression) expressionNode()      {}
+func (se *SwitchExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SwitchExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("switch ")
+	out.WriteString(se.Subject.String())
+	out.WriteString(" { ")
+	for _, c := range se.Cases {
+		values := []string{}
+		for _, v := range c.Values {
+			values = append(values, v.String())
+		}
+		out.WriteString("case ")
+		out.WriteString(strings.Join(values, ", "))
+		out.WriteString(" -> ")
+		out.WriteString(c.Body.String())
+		out.WriteString("; ")
+	}
+	if se.Default != nil {
+		out.WriteString("default -> ")
+		out.WriteString(se.Default.String())
+		out.WriteString("; ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
@@ -0,0 +1,121 @@
+package ast
+
+import (
+	"bytes"
+	"monkey/token"
+	"strings"
+)
+
+// ===================
+// Patterns
+// ===================
+
+// A Pattern describes the shape of a value. Matching a value against a pattern
+// either fails or binds the names the pattern introduces.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+// Matches anything and binds nothing: _
+type WildcardPattern struct {
+	Token token.Token
+}
+
+func (wp *WildcardPattern) patternNode()         {}
+func (wp *WildcardPattern) TokenLiteral() string { return wp.Token.Literal }
+func (wp *WildcardPattern) String() string       { return "_" }
+
+// Matches anything and binds it to a name: x
+type BindingPattern struct {
+	Token token.Token
+	Name  *Identifier
+}
+
+func (bp *BindingPattern) patternNode()         {}
+func (bp *BindingPattern) TokenLiteral() string { return bp.Token.Literal }
+func (bp *BindingPattern) String() string       { return bp.Name.String() }
+
+// Matches values equal to a literal: 1, -2, "a", true
+type LiteralPattern struct {
+	Token token.Token
+	Value Expression
+}
+
+func (lp *LiteralPattern) patternNode()         {}
+func (lp *LiteralPattern) TokenLiteral() string { return lp.Token.Literal }
+func (lp *LiteralPattern) String() string       { return lp.Value.String() }
+
+// Matches a variant of an enum and its fields: Circle(r), Shape.Rect(w, h), Empty.
+// Against a value that is not an enum value, a lone unqualified name such as Empty
+// binds like a BindingPattern.
+type VariantPattern struct {
+	Token     token.Token
+	Enum      *Identifier // nil when the variant is not qualified
+	Name      *Identifier
+	Arguments []Pattern // nil when written without parentheses
+}
+
+func (vp *VariantPattern) patternNode()         {}
+func (vp *VariantPattern) TokenLiteral() string { return vp.Token.Literal }
+func (vp *VariantPattern) String() string {
+	var out bytes.Buffer
+
+	if vp.Enum != nil {
+		out.WriteString(vp.Enum.String())
+		out.WriteString(".")
+	}
+	out.WriteString(vp.Name.String())
+
+	if vp.Arguments != nil {
+		args := []string{}
+		for _, a := range vp.Arguments {
+			args = append(args, a.String())
+		}
+
+		out.WriteString("(")
+		out.WriteString(strings.Join(args, ", "))
+		out.WriteString(")")
+	}
+
+	return out.String()
+}
+
+// Matches an array element by element, collecting any remainder: [a, b, ...rest]
+type ArrayPattern struct {
+	Token    token.Token // the [ token
+	Elements []Pattern
+	Rest     *Identifier // nil when the array must match exactly
+}
+
+func (ap *ArrayPattern) patternNode()         {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) String() string {
+	elements := []string{}
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// Matches hashes, structs and instances by key: {"name": n, age}
+type HashPattern struct {
+	Token  token.Token // the { token
+	Keys   []string
+	Values []Pattern
+}
+
+func (hp *HashPattern) patternNode()         {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) String() string {
+	pairs := []string{}
+	for i, key := range hp.Keys {
+		pairs = append(pairs, key+": "+hp.Values[i].String())
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
@@ -0,0 +1,296 @@
+package evaluator
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"monkey/object"
+	"os"
+	"time"
+	"unicode/utf8"
+)
+
+func (interp *Interpreter) defaultBuiltins() map[string]*object.Builtin {
+	return map[string]*object.Builtin{
+		// round(d, places, mode) rounds a decimal to the given number of digits after the
+		// decimal point. mode is one of "half_even" (the default), "half_up" or "down".
+		"round": {Fn: builtinRound},
+		"len":   {Fn: builtinLen},
+		"first": {Fn: builtinFirst},
+		"last":  {Fn: builtinLast},
+		"rest":  {Fn: builtinRest},
+		"push":  {Fn: builtinPush},
+		"next":  {Fn: builtinNext},
+		"error": {Fn: builtinError},
+		// ok(v) and err(e) make the variants of Result, which is_ok, is_err and unwrap
+		// take apart
+		"ok":     {Fn: builtinOk},
+		"err":    {Fn: builtinErr},
+		"is_ok":  {Fn: builtinIsOk},
+		"is_err": {Fn: builtinIsErr},
+		"unwrap": {Fn: builtinUnwrap},
+		// format("{} costs {price:.2f}", n, {"price": p}) and sprintf("%5d", n) build
+		// strings from templates
+		"format":  {Fn: builtinFormat},
+		"sprintf": {Fn: builtinSprintf},
+		// puts(args...) prints every argument on its own line
+		"puts": {Fn: func(args ...object.Object) object.Object {
+			for _, arg := range args {
+				fmt.Fprintln(interp.stdout, arg.Inspect())
+			}
+			return NULL
+		}},
+		// warn(args...) is puts for the error output
+		"warn": {Fn: func(args ...object.Object) object.Object {
+			for _, arg := range args {
+				fmt.Fprintln(interp.stderr, arg.Inspect())
+			}
+			return NULL
+		}},
+		// read_file(path) returns the contents of a file below the fs.read root
+		"read_file": {Fn: interp.builtinReadFile},
+		// write_file(path, contents) replaces a file below the fs.write root
+		"write_file": {Fn: interp.builtinWriteFile},
+		// getenv(name) returns an environment variable, or null if it is not set
+		"getenv": {Fn: interp.builtinGetenv},
+		// now() returns the milliseconds since the Unix epoch
+		"now": {Fn: interp.builtinNow},
+		// rand(n) returns a random integer in [0, n)
+		"rand": {Fn: interp.builtinRand},
+	}
+}
+
+func builtinLen(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.String:
+		// Characters are counted, as strings are sliced and iterated by character
+		return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+	case *object.Array:
+		return &object.Integer{Value: int64(len(arg.Elements))}
+	case *object.Hash:
+		return &object.Integer{Value: int64(len(arg.Pairs))}
+	default:
+		return newError("argument to `len` not supported, got %s", args[0].Type())
+	}
+}
+
+func builtinFirst(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `first` must be ARRAY, got %s", args[0].Type())
+	}
+
+	if len(arr.Elements) > 0 {
+		return arr.Elements[0]
+	}
+	return NULL
+}
+
+func builtinLast(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `last` must be ARRAY, got %s", args[0].Type())
+	}
+
+	length := len(arr.Elements)
+	if length > 0 {
+		return arr.Elements[length-1]
+	}
+	return NULL
+}
+
+func builtinRest(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `rest` must be ARRAY, got %s", args[0].Type())
+	}
+
+	length := len(arr.Elements)
+	if length > 0 {
+		newElements := make([]object.Object, length-1)
+		copy(newElements, arr.Elements[1:length])
+		return &object.Array{Elements: newElements}
+	}
+	return NULL
+}
+
+func builtinPush(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `push` must be ARRAY, got %s", args[0].Type())
+	}
+
+	length := len(arr.Elements)
+	newElements := make([]object.Object, length+1)
+	copy(newElements, arr.Elements)
+	newElements[length] = args[1]
+
+	return &object.Array{Elements: newElements}
+}
+
+// The most digits after the decimal point round and the formatting builtins produce.
+// Larger scales would let a single call allocate huge numbers before the allocation
+// limits could react.
+const maxPrecision = 10000
+
+func builtinRound(args ...object.Object) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=2 or 3", len(args))
+	}
+
+	if !isNumeric(args[0]) {
+		return newError("argument to `round` must be DECIMAL or INTEGER, got %s", args[0].Type())
+	}
+
+	places, ok := args[1].(*object.Integer)
+	if !ok {
+		return newError("places passed to `round` must be INTEGER, got %s", args[1].Type())
+	}
+	if places.Value < 0 {
+		return newError("places passed to `round` must not be negative, got %d", places.Value)
+	}
+	if places.Value > maxPrecision {
+		return newError("places passed to `round` must be at most %d, got %d", maxPrecision, places.Value)
+	}
+
+	mode := object.RoundHalfEven
+	if len(args) == 3 {
+		name, ok := args[2].(*object.String)
+		if !ok {
+			return newError("mode passed to `round` must be STRING, got %s", args[2].Type())
+		}
+		mode, ok = object.LookupRoundingMode(name.Value)
+		if !ok {
+			return newError("unknown rounding mode: %q", name.Value)
+		}
+	}
+
+	return toDecimal(args[0]).Rescale(int(places.Value), mode)
+}
+
+func (interp *Interpreter) builtinReadFile(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	path, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `read_file` must be STRING, got %s", args[0].Type())
+	}
+
+	root := interp.capabilities.FSReadRoot
+	if root == "" {
+		return newPermissionError("read_file requires the fs.read capability")
+	}
+	opened, rel, err := openWithin(root, path.Value)
+	if err != nil {
+		return newPermissionError("read_file: %s", err)
+	}
+	defer opened.Close()
+
+	contents, err := opened.ReadFile(rel)
+	if err != nil {
+		if isSymlink(opened, rel) {
+			return newPermissionError("read_file: %s", outsideError(root, path.Value))
+		}
+		return newError("read_file: could not read %q", path.Value)
+	}
+	return &object.String{Value: string(contents)}
+}
+
+func (interp *Interpreter) builtinWriteFile(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	path, ok := args[0].(*object.String)
+	if !ok {
+		return newError("first argument to `write_file` must be STRING, got %s", args[0].Type())
+	}
+	contents, ok := args[1].(*object.String)
+	if !ok {
+		return newError("second argument to `write_file` must be STRING, got %s", args[1].Type())
+	}
+
+	root := interp.capabilities.FSWriteRoot
+	if root == "" {
+		return newPermissionError("write_file requires the fs.write capability")
+	}
+	opened, rel, err := openWithin(root, path.Value)
+	if err != nil {
+		return newPermissionError("write_file: %s", err)
+	}
+	defer opened.Close()
+
+	if err := opened.WriteFile(rel, []byte(contents.Value), 0o644); err != nil {
+		if isSymlink(opened, rel) {
+			return newPermissionError("write_file: %s", outsideError(root, path.Value))
+		}
+		return newError("write_file: could not write %q", path.Value)
+	}
+	return NULL
+}
+
+func (interp *Interpreter) builtinGetenv(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	name, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `getenv` must be STRING, got %s", args[0].Type())
+	}
+
+	if !interp.capabilities.Env {
+		return newPermissionError("getenv requires the env capability")
+	}
+
+	value, ok := os.LookupEnv(name.Value)
+	if !ok {
+		return NULL
+	}
+	return &object.String{Value: value}
+}
+
+func (interp *Interpreter) builtinNow(args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+
+	if !interp.capabilities.Clock {
+		return newPermissionError("now requires the clock capability")
+	}
+
+	return &object.Integer{Value: time.Now().UnixMilli()}
+}
+
+func (interp *Interpreter) builtinRand(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	n, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("argument to `rand` must be INTEGER, got %s", args[0].Type())
+	}
+	if n.Value <= 0 {
+		return newError("argument to `rand` must be positive, got %d", n.Value)
+	}
+
+	if !interp.capabilities.Rand {
+		return newPermissionError("rand requires the rand capability")
+	}
+
+	return &object.Integer{Value: rand.Int64N(n.Value)}
+}
@@ -0,0 +1,102 @@
+package evaluator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"monkey/object"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The Cause of errors returned when a program calls a builtin it has not been
+// granted the capability for
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Capabilities control which parts of the outside world a program can reach through
+// builtins. The zero value grants nothing, which is what untrusted programs should
+// get.
+type Capabilities struct {
+	// Directory that read_file may read from. Empty denies all reads.
+	FSReadRoot string
+	// Directory that write_file may write to. Empty denies all writes.
+	FSWriteRoot string
+	// Allows getenv to read environment variables
+	Env bool
+	// Allows now to read the current time
+	Clock bool
+	// Allows rand to generate random numbers
+	Rand bool
+}
+
+// Grants every capability, with file access confined to root
+func AllCapabilities(root string) Capabilities {
+	return Capabilities{FSReadRoot: root, FSWriteRoot: root, Env: true, Clock: true, Rand: true}
+}
+
+func WithCapabilities(capabilities Capabilities) Option {
+	return func(interp *Interpreter) { interp.capabilities = capabilities }
+}
+
+func (interp *Interpreter) Capabilities() Capabilities {
+	return interp.capabilities
+}
+
+func newPermissionError(format string, a ...interface{}) *object.Error {
+	message := "permission denied: " + fmt.Sprintf(format, a...)
+	return &object.Error{Message: message, Kind: "PermissionError", Cause: ErrPermissionDenied}
+}
+
+// Opens root and resolves path within it, returning the path relative to root. The
+// check follows the symlinks that exist now, which gives a clear error for paths that
+// lead out of root, but files must then be accessed through the returned Root: it
+// refuses to follow symlinks out of root even if they are created or changed after
+// the check, or point at files that do not exist yet.
+func openWithin(root string, path string) (*os.Root, string, error) {
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, "", err
+	}
+	resolvedRoot, err = filepath.Abs(resolvedRoot)
+	if err != nil {
+		return nil, "", err
+	}
+
+	target := filepath.Join(resolvedRoot, filepath.FromSlash(path))
+	resolved, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		dir, dirErr := filepath.EvalSymlinks(filepath.Dir(target))
+		if dirErr != nil {
+			return nil, "", err
+		}
+		resolved = filepath.Join(dir, filepath.Base(target))
+	}
+
+	if rel, err := filepath.Rel(resolvedRoot, resolved); err != nil || escapes(rel) {
+		return nil, "", outsideError(root, path)
+	}
+
+	opened, err := os.OpenRoot(resolvedRoot)
+	if err != nil {
+		return nil, "", err
+	}
+	rel, _ := filepath.Rel(resolvedRoot, target)
+	return opened, rel, nil
+}
+
+// Reports whether rel names a symlink. Operations through a Root only fail on a
+// symlink that passed openWithin if it leads out of the root, perhaps to a file
+// that does not exist yet.
+func isSymlink(root *os.Root, rel string) bool {
+	info, err := root.Lstat(rel)
+	return err == nil && info.Mode()&fs.ModeSymlink != 0
+}
+
+func outsideError(root string, path string) error {
+	return fmt.Errorf("%q is outside of %q", path, root)
+}
+
+func escapes(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,99 @@
+package evaluator
+
+import (
+	"errors"
+	"monkey/object"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCapabilitiesDeniedByDefault(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`read_file("/etc/passwd")`, "permission denied: read_file requires the fs.read capability"},
+		{`write_file("out.txt", "x")`, "permission denied: write_file requires the fs.write capability"},
+		{`getenv("HOME")`, "permission denied: getenv requires the env capability"},
+		{`now()`, "permission denied: now requires the clock capability"},
+		{`rand(10)`, "permission denied: rand requires the rand capability"},
+	}
+
+	for _, tt := range tests {
+		testPermissionError(t, testEval(tt.input), tt.expectedMessage)
+	}
+}
+
+func TestFileCapabilities(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	// A dangling link that write_file would create its target through
+	if err := os.Symlink(filepath.Join(outside, "created.txt"), filepath.Join(root, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+
+	interp := New(WithCapabilities(Capabilities{FSReadRoot: root, FSWriteRoot: root}))
+
+	testNullObject(t, evalWith(interp, `write_file("report.txt", "total: 42")`))
+	testStringObject(t, evalWith(interp, `read_file("report.txt")`), "total: 42")
+	testStringObject(t, evalWith(interp, `read_file("/report.txt")`), "total: 42")
+
+	escapes := []string{
+		`read_file("../secret.txt")`,
+		`read_file("link/secret.txt")`,
+		`write_file("link/secret.txt", "overwritten")`,
+		`write_file("dangling", "created")`,
+		`read_file("dangling")`,
+	}
+	for _, input := range escapes {
+		errObj, ok := evalWith(interp, input).(*object.Error)
+		if !ok || !errors.Is(errObj.Cause, ErrPermissionDenied) {
+			t.Errorf("expected permission error for %s. got=%+v", input, errObj)
+		}
+	}
+
+	contents, _ := os.ReadFile(filepath.Join(outside, "secret.txt"))
+	if string(contents) != "secret" {
+		t.Errorf("file outside of the root was modified. got=%q", contents)
+	}
+	if _, err := os.Lstat(filepath.Join(outside, "created.txt")); err == nil {
+		t.Errorf("file outside of the root was created through a dangling link")
+	}
+
+	readOnly := New(WithCapabilities(Capabilities{FSReadRoot: root}))
+	testPermissionError(t, evalWith(readOnly, `write_file("report.txt", "")`),
+		"permission denied: write_file requires the fs.write capability")
+}
+
+func TestGrantedCapabilities(t *testing.T) {
+	t.Setenv("MONKEY_TEST_VAR", "banana")
+	interp := New(WithCapabilities(Capabilities{Env: true, Clock: true, Rand: true}))
+
+	testStringObject(t, evalWith(interp, `getenv("MONKEY_TEST_VAR")`), "banana")
+	testNullObject(t, evalWith(interp, `getenv("MONKEY_TEST_UNSET_VAR")`))
+	testBooleanObject(t, evalWith(interp, `now() > 0`), true)
+	testBooleanObject(t, evalWith(interp, `let r = rand(10); if (r < 0) { false } else { r < 10 }`), true)
+}
+
+func testPermissionError(t *testing.T, obj object.Object, expected string) {
+	t.Helper()
+
+	errObj, ok := obj.(*object.Error)
+	if !ok {
+		t.Errorf("no error object returned. got=%T(%+v)", obj, obj)
+		return
+	}
+	if errObj.Message != expected {
+		t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+	}
+	if !errors.Is(errObj.Cause, ErrPermissionDenied) {
+		t.Errorf("error is not a permission error. got=%v", errObj.Cause)
+	}
+}
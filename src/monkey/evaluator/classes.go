@@ -0,0 +1,87 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// ===================
+// Classes
+// ===================
+func (interp *Interpreter) evalClassStatement(node *ast.ClassStatement, env *object.Environment) object.Object {
+	class := &object.Class{Name: node.Name.Value, Methods: make(map[string]*object.Function)}
+
+	if node.Parent != nil {
+		parent := interp.evalIdentifier(node.Parent, env)
+		if isError(parent) {
+			return parent
+		}
+		parentClass, ok := parent.(*object.Class)
+		if !ok {
+			return newError("superclass of %s must be a class, got %s", class.Name, parent.Type())
+		}
+		class.Parent = parentClass
+	}
+
+	for _, method := range node.Methods {
+		if _, ok := class.Methods[method.Name]; ok {
+			return newError("duplicate method %s in class %s", method.Name, class.Name)
+		}
+		class.Methods[method.Name] = &object.Function{
+			Name:       class.Name + "." + method.Name,
+			Parameters: method.Parameters,
+			Patterns:   method.Patterns,
+			Defaults:   method.Defaults,
+			Rest:       method.Rest,
+			Body:       method.Body,
+			Env:        env,
+			Generator:  method.Generator,
+		}
+	}
+
+	env.Set(class.Name, interp.track(class))
+	return nil
+}
+
+// Creates an instance of the class and passes it, along with the arguments, to init
+func (interp *Interpreter) instantiate(class *object.Class, args []object.Object, named []namedArgument) object.Object {
+	instance := object.NewInstance(class)
+	if err := interp.allocate(sizeOf(instance)); err != nil {
+		return err
+	}
+
+	init, ok := class.Method("init")
+	if !ok {
+		if len(args)+len(named) != 0 {
+			return newError("wrong number of arguments. got=%d, want=0", len(args)+len(named))
+		}
+		return instance
+	}
+
+	result := interp.callFunction(init, instance, args, named)
+	if isError(result) {
+		return result
+	}
+
+	return instance
+}
+
+func (interp *Interpreter) evalInstanceMember(instance *object.Instance, name string) object.Object {
+	if value, ok := instance.Get(name); ok {
+		return value
+	}
+
+	if method, ok := instance.Class.Method(name); ok {
+		return interp.track(&object.BoundMethod{Receiver: instance, Method: method})
+	}
+
+	return newError("%s has no field or method %s", instance.Class.Name, name)
+}
+
+func evalClassMember(class *object.Class, name string) object.Object {
+	if method, ok := class.Method(name); ok {
+		return method
+	}
+
+	return newError("%s has no method %s", class.Name, name)
+}
@@ -0,0 +1,67 @@
+package evaluator
+
+import "testing"
+
+const counterClass = `
+class Counter {
+	fn init(self, start) { self.count = start; }
+	fn inc(self) { self.count = self.count + 1; self.count }
+	fn add(self, n) { self.count = self.count + n; self }
+}
+`
+
+func TestClasses(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{counterClass + `let c = Counter(5); c.inc(); c.inc()`, 7},
+		{counterClass + `let c = Counter(0); c.add(2).add(3).count`, 5},
+		{counterClass + `let c = Counter(0); let inc = c.inc; inc(); inc(); c.count`, 2},
+		{counterClass + `let a = Counter(0); let b = Counter(10); a.inc(); b.count`, 10},
+		{counterClass + `let a = Counter(1); a == a`, true},
+		{counterClass + `Counter(1) == Counter(1)`, false},
+		{`class Empty {}; let e = Empty(); e.name = "x"; e.name`, "x"},
+		{`let x = 10; class Adder { fn add(self, y) { x + y } }; Adder().add(5)`, 15},
+		{counterClass + `Counter(1).reset()`, errorMessage("Counter has no field or method reset")},
+		{counterClass + `Counter(1).missing`, errorMessage("Counter has no field or method missing")},
+		{counterClass + `Counter.missing`, errorMessage("Counter has no method missing")},
+		{counterClass + `Counter()`, errorMessage("wrong number of arguments. got=0, want=1")},
+		{counterClass + `Counter(1).add()`, errorMessage("wrong number of arguments. got=0, want=1")},
+		{`class Empty {}; Empty(1)`, errorMessage("wrong number of arguments. got=1, want=0")},
+		{`class A { fn f(self) { 1 } fn f(self) { 2 } }`, errorMessage("duplicate method f in class A")},
+		{`let A = 1; class B < A {}`, errorMessage("superclass of B must be a class, got INTEGER")},
+		{`class A { fn init(self) { self.x + 1 } }; A()`, errorMessage("A has no field or method x")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestClassInheritance(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{counterClass + `class Loud < Counter {}; Loud(3).inc()`, 4},
+		{counterClass + `class Double < Counter { fn inc(self) { self.add(2).count } }; Double(0).inc()`, 2},
+		{counterClass + `class Tens < Counter { fn init(self) { Counter.init(self, 10) } }; Tens().inc()`, 11},
+		{counterClass + `class Loud < Counter { fn inc(self) { Counter.inc(self) * 100 } }; Loud(1).inc()`, 200},
+		{counterClass + `class A < Counter {}; class B < A {}; B(1).add(1).inc()`, 3},
+		{counterClass + `class Loud < Counter {}; Loud(1).shout()`, errorMessage("Loud has no field or method shout")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestInstanceInspect(t *testing.T) {
+	evaluated := testEval(counterClass + `let c = Counter(1); c.label = "a"; c`)
+
+	expected := "Counter{count: 1, label: a}"
+	if evaluated.Inspect() != expected {
+		t.Errorf("wrong Inspect. expected=%q, got=%q", expected, evaluated.Inspect())
+	}
+}
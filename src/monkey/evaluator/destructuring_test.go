@@ -0,0 +1,49 @@
+package evaluator
+
+import "testing"
+
+func TestLetDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let [a, b] = [1, 2]; a + b`, 3},
+		{`let [a, b, ...rest] = [1, 2, 3, 4]; len(rest)`, 2},
+		{`let [a, ...rest] = [1]; len(rest)`, 0},
+		{`let [[a, b], c] = [[1, 2], 3]; a + b + c`, 6},
+		{`let [_, second] = [1, 2]; second`, 2},
+		{`let {name, age} = {"name": "ann", "age": 30}; name`, "ann"},
+		{`let {name: n, "age": a} = {"name": "ann", "age": 30}; a`, 30},
+		{`let {pos: [x, y]} = {"pos": [3, 4]}; x * y`, 12},
+		{`struct P { x, y }; let {x, y} = P(5, 6); x + y`, 11},
+		{`let [a, b] = [1]`, errorMessage("cannot destructure [1] with [a, b]")},
+		{`let [a] = [1, 2]`, errorMessage("cannot destructure [1, 2] with [a]")},
+		{`let [a] = 5`, errorMessage("cannot destructure 5 with [a]")},
+		{`let {name, age} = {"name": "ann"}`, errorMessage("cannot destructure {name: ann} with {name: name, age: age}")},
+		{`let [a, b] = [1, 2, 3]; a`, errorMessage("cannot destructure [1, 2, 3] with [a, b]")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestParameterDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let add = fn([a, b]) { a + b }; add([1, 2])`, 3},
+		{`let f = fn(x, [a, ...rest], {k}) { x + a + len(rest) + k }; f(1, [2, 3, 4], {"k": 5})`, 10},
+		{`let greet = fn({name}) { "hi " + name }; greet({"name": "bob"})`, "hi bob"},
+		{`[[1, 2], [3, 4]].map(fn([a, b]) { a * b }).reduce(fn(acc, x) { acc + x }, 0)`, 14},
+		{`class Pt { fn init(self, [x, y]) { self.x = x; self.y = y; } }; Pt([7, 8]).y`, 8},
+		{`let f = fn([a, b]) { a }; f([1])`, errorMessage("cannot destructure [1] with [a, b]")},
+		{`let f = fn({name}) { name }; f(1)`, errorMessage("cannot destructure 1 with {name: name}")},
+		{`let f = fn([a, b]) { a }; f()`, errorMessage("wrong number of arguments. got=0, want=1")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
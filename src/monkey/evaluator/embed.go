@@ -0,0 +1,69 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"monkey/object"
+	"reflect"
+)
+
+// Returns the value bound to name in the global environment, e.g. a function
+// defined by a script with `let handler = fn(req) { ... }`.
+func (interp *Interpreter) Get(name string) (object.Object, bool) {
+	return interp.globals.Get(name)
+}
+
+// Binds name in the global environment to a Go value. Functions become callable
+// from programs, with their arguments and results converted automatically; other
+// values are converted with object.FromGo.
+func (interp *Interpreter) Define(name string, value any) error {
+	var obj object.Object
+	var err error
+
+	if reflect.ValueOf(value).Kind() == reflect.Func {
+		obj, err = object.NewGoFunction(name, value)
+	} else {
+		obj, err = object.FromGo(value)
+	}
+	if err != nil {
+		return fmt.Errorf("define %s: %w", name, err)
+	}
+
+	interp.globals.Set(name, obj)
+	return nil
+}
+
+// Calls a Monkey function or builtin with the given arguments, which are converted
+// with object.FromGo. Errors produced by the call are returned as *object.Error.
+// Calling nil, as returned by Get for a missing name, or a value that cannot be
+// called is an error.
+func (interp *Interpreter) Call(ctx context.Context, fn object.Object, args ...any) (object.Object, error) {
+	switch fn.(type) {
+	case *object.Function, *object.BoundMethod, *object.Class, *object.EnumVariant, *object.Builtin, *object.StructType:
+	case nil:
+		return nil, errors.New("cannot call nil")
+	default:
+		return nil, fmt.Errorf("not a function: %s", fn.Type())
+	}
+
+	defer interp.withContext(ctx)()
+
+	converted := make([]object.Object, len(args))
+	for i, arg := range args {
+		obj, err := object.FromGo(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		converted[i] = obj
+	}
+
+	result := interp.applyFunction(fn, converted)
+	if err, ok := result.(*object.Error); ok {
+		return nil, err
+	}
+	if result == nil {
+		return NULL, nil
+	}
+	return result, nil
+}
@@ -0,0 +1,252 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"monkey/object"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCallMonkeyFunctionFromGo(t *testing.T) {
+	interp := New()
+	evalWith(interp, `
+let handler = fn(req) {
+  { "status": 200, "body": "hello " + req["name"], "sizes": [len(req["name"]), req["n"] * 2] }
+};`)
+
+	handler, ok := interp.Get("handler")
+	if !ok {
+		t.Fatalf("handler not defined")
+	}
+
+	result, err := interp.Call(context.Background(), handler, map[string]any{"name": "monkey", "n": 21})
+	if err != nil {
+		t.Fatalf("call failed: %s", err)
+	}
+
+	converted, err := object.ToGo(result)
+	if err != nil {
+		t.Fatalf("conversion failed: %s", err)
+	}
+
+	expected := map[string]any{
+		"status": int64(200),
+		"body":   "hello monkey",
+		"sizes":  []any{int64(6), int64(42)},
+	}
+	if !reflect.DeepEqual(converted, expected) {
+		t.Errorf("wrong result. got=%#v, want=%#v", converted, expected)
+	}
+
+	if _, ok := interp.Get("missing"); ok {
+		t.Errorf("expected missing to be undefined")
+	}
+}
+
+func TestCallReturnsErrors(t *testing.T) {
+	interp := New()
+	evalWith(interp, `let add = fn(a, b) { a + b };`)
+	add, _ := interp.Get("add")
+
+	_, err := interp.Call(context.Background(), add, 1, true)
+	if err == nil || err.Error() != "type mismatch: INTEGER + BOOLEAN" {
+		t.Errorf("expected type mismatch. got=%v", err)
+	}
+
+	_, err = interp.Call(context.Background(), add, 1)
+	if err == nil || err.Error() != "wrong number of arguments. got=1, want=2" {
+		t.Errorf("expected arity error. got=%v", err)
+	}
+
+	missing, _ := interp.Get("missing")
+	_, err = interp.Call(context.Background(), missing)
+	if err == nil || err.Error() != "cannot call nil" {
+		t.Errorf("expected error calling a missing function. got=%v", err)
+	}
+
+	_, err = interp.Call(context.Background(), &object.Integer{Value: 1})
+	if err == nil || err.Error() != "not a function: INTEGER" {
+		t.Errorf("expected error calling an integer. got=%v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = interp.Call(ctx, add, 1, 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation. got=%v", err)
+	}
+}
+
+func TestDefineGoFunctions(t *testing.T) {
+	interp := New()
+
+	var logged []string
+	must(t, interp.Define("log", func(msg string) { logged = append(logged, msg) }))
+	must(t, interp.Define("sum", func(values ...int) int {
+		total := 0
+		for _, v := range values {
+			total += v
+		}
+		return total
+	}))
+	must(t, interp.Define("lookup", func(users map[string]int, name string) (int, error) {
+		age, ok := users[name]
+		if !ok {
+			return 0, fmt.Errorf("no user %q", name)
+		}
+		return age, nil
+	}))
+	must(t, interp.Define("words", strings.Fields))
+	must(t, interp.Define("apply", func(fn object.Object, arg int64) (object.Object, error) {
+		return interp.Call(context.Background(), fn, arg)
+	}))
+	must(t, interp.Define("limit", 100))
+	must(t, interp.Define("crash", func(values []int) int { return values[5] }))
+	must(t, interp.Define("fail", func() { panic("out of cheese") }))
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`log("hello"); log("world")`, nil},
+		{`sum()`, 0},
+		{`sum(1, 2, 3) + limit`, 106},
+		{`lookup({"ann": 31}, "ann")`, 31},
+		{`lookup({"ann": 31}, "bob")`, `lookup: no user "bob"`},
+		{`len(words(" a b  c "))`, 3},
+		{`apply(fn(x) { x * 2 }, 21)`, 42},
+		{`log(1)`, "argument 1 to `log`: cannot convert INTEGER to string"},
+		{`log("a", "b")`, "wrong number of arguments to `log`. got=2, want=1"},
+		{`crash([1])`, "panic in `crash`: runtime error: index out of range [5] with length 1"},
+		{`fail()`, "panic in `fail`: out of cheese"},
+	}
+
+	for _, tt := range tests {
+		evaluated := evalWith(interp, tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case nil:
+			testNullObject(t, evaluated)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(logged, []string{"hello", "world"}) {
+		t.Errorf("log was not called. got=%v", logged)
+	}
+
+	if err := interp.Define("bad", func() (int, int) { return 0, 0 }); err == nil {
+		t.Errorf("expected an error for a function returning two values")
+	}
+	if err := interp.Define("bad", 1.5); err == nil {
+		t.Errorf("expected an error for a float")
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+type account struct {
+	Owner   string
+	Balance int64
+	Tags    []string
+	secret  string
+}
+
+func (a *account) Deposit(amount int64) int64 {
+	a.Balance += amount
+	return a.Balance
+}
+
+func (a account) Describe(prefix string) string {
+	return fmt.Sprintf("%s%s: %d", prefix, a.Owner, a.Balance)
+}
+
+type record struct {
+	ID int64
+}
+
+type user struct {
+	*record
+	Name string
+}
+
+func TestHostObjects(t *testing.T) {
+	acct := &account{Owner: "ann", Balance: 10, Tags: []string{"vip"}, secret: "x"}
+
+	interp := New()
+	must(t, interp.Define("acct", acct))
+	must(t, interp.Define("saved", &user{record: &record{ID: 7}, Name: "a"}))
+	must(t, interp.Define("unsaved", &user{Name: "a"}))
+	must(t, interp.Define("copy", account{Owner: "bob"}))
+	must(t, interp.Define("owner", func(a *account) string { return a.Owner }))
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`acct["Owner"]`, "ann"},
+		{`acct["Balance"] + 1`, 11},
+		{`acct["Tags"][0]`, "vip"},
+		{`acct["Deposit"](5)`, 15},
+		{`acct["Balance"]`, 15},
+		{`acct["Describe"]("account ")`, "account ann: 15"},
+		{`owner(acct)`, "ann"},
+		{`copy["Deposit"](1); copy["Balance"]`, 1},
+		{`owner(copy)`, "bob"},
+		{`acct["secret"]`, errorMessage("cannot access unexported field secret of evaluator.account")},
+		{`acct["Missing"]`, errorMessage("evaluator.account has no field or method Missing")},
+		{`acct["Deposit"]("five")`, errorMessage("argument 1 to `evaluator.account.Deposit`: cannot convert STRING to int64")},
+		{`owner(1)`, errorMessage("argument 1 to `owner`: cannot convert INTEGER to *evaluator.account")},
+		{`acct.Owner`, "ann"},
+		{`acct.Deposit(5)`, 20},
+		{`acct.Describe("")`, "ann: 20"},
+		{`acct.secret`, errorMessage("cannot access unexported field secret of evaluator.account")},
+		{`saved.ID`, 7},
+		{`unsaved.Name`, "a"},
+		{`unsaved.ID`, errorMessage("field ID of evaluator.user: reflect: indirection through nil pointer to embedded struct field record")},
+	}
+
+	for _, tt := range tests {
+		evaluated := evalWith(interp, tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testStringObject(t, evaluated, expected)
+		case errorMessage:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != string(expected) {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+
+	if acct.Balance != 20 {
+		t.Errorf("method call did not update the Go value. got=%d", acct.Balance)
+	}
+}
+
+type errorMessage string
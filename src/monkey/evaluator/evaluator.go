@@ -2,59 +2,153 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"monkey/ast"
 	"monkey/object"
+	"strings"
 )
 
 var (
-	NULL  = &object.Null{}
-	TRUE  = &object.Boolean{Value: true}
-	FALSE = &object.Boolean{Value: false}
+	NULL  = object.NULL
+	TRUE  = object.TRUE
+	FALSE = object.FALSE
 )
 
 // ===================
 // Main Evaluation Body
 // ===================
-func Eval(node ast.Node) object.Object {
+func (interp *Interpreter) eval(node ast.Node, env *object.Environment) object.Object {
+	if err := interp.step(); err != nil {
+		return err
+	}
+
 	switch node := node.(type) {
 
 	// Statements
 	case *ast.Program:
-		return evalProgram(node.Statements)
+		return interp.evalProgram(node.Statements, env)
 	case *ast.ExpressionStatement:
-		return Eval(node.Expression)
+		return interp.eval(node.Expression, env)
 	case *ast.BlockStatement:
-		return evalBlockStatements(node.Statements)
+		return interp.evalBlockStatements(node.Statements, env)
 	case *ast.ReturnStatement:
-		rv := Eval(node.ReturnValue)
+		rv := interp.eval(node.ReturnValue, env)
 		if isError(rv) {
 			return rv
 		}
 		return &object.ReturnValue{Value: rv}
+	case *ast.LetStatement:
+		val := interp.eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		if node.Pattern == nil {
+			// Functions are known by the name they are first bound to
+			if fn, ok := val.(*object.Function); ok && fn.Name == "" {
+				fn.Name = node.Name.Value
+			}
+			env.Set(node.Name.Value, val)
+		} else if err := interp.destructure(node.Pattern, val, env); err != nil {
+			return err
+		}
+	case *ast.StructStatement:
+		return interp.evalStructStatement(node, env)
+	case *ast.ClassStatement:
+		return interp.evalClassStatement(node, env)
+	case *ast.EnumStatement:
+		return interp.evalEnumStatement(node, env)
 
 	// Expressions
 	case *ast.IntegerLiteral:
-		return &object.Integer{Value: node.Value}
+		return interp.track(&object.Integer{Value: node.Value})
+	case *ast.BigIntegerLiteral:
+		return interp.track(normalizeBigInt(new(big.Int).Set(node.Value)))
+	case *ast.DecimalLiteral:
+		return interp.track(object.NewDecimal(new(big.Int).Set(node.Value), node.Scale))
+	case *ast.StringLiteral:
+		return interp.track(&object.String{Value: node.Value})
+	case *ast.TemplateLiteral:
+		return interp.evalTemplateLiteral(node, env)
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
+	case *ast.Identifier:
+		return interp.evalIdentifier(node, env)
 	case *ast.PrefixExpression:
-		right := Eval(node.Right)
+		right := interp.eval(node.Right, env)
 		if isError(right) {
 			return right
 		}
-		return evalPrefixExpression(node.Operator, right)
+		return interp.track(interp.evalPrefixExpression(node.Operator, right))
 	case *ast.InfixExpression:
-		left := Eval(node.Left)
+		left := interp.eval(node.Left, env)
 		if isError(left) {
 			return left
 		}
-		right := Eval(node.Right)
+		right := interp.eval(node.Right, env)
 		if isError(right) {
 			return right
 		}
-		return evalInfixExpression(node.Operator, left, right)
+		return interp.track(interp.evalInfixExpression(node.Operator, left, right))
 	case *ast.IfExpression:
-		return evalIfExpression(node)
+		return interp.evalIfExpression(node, env)
+	case *ast.WhileExpression:
+		return interp.evalWhileExpression(node, env)
+	case *ast.FunctionLiteral:
+		return interp.track(&object.Function{
+			Parameters: node.Parameters,
+			Patterns:   node.Patterns,
+			Defaults:   node.Defaults,
+			Rest:       node.Rest,
+			Body:       node.Body,
+			Env:        env,
+			Generator:  node.Generator,
+		})
+	case *ast.CallExpression:
+		result, _ := interp.evalChain(node, env)
+		return result
+	case *ast.ArrayLiteral:
+		elements := interp.evalExpressions(node.Elements, env)
+		if len(elements) == 1 && isError(elements[0]) {
+			return elements[0]
+		}
+		return interp.track(&object.Array{Elements: elements})
+	case *ast.IndexExpression:
+		result, _ := interp.evalChain(node, env)
+		return result
+	case *ast.HashLiteral:
+		return interp.evalHashLiteral(node, env)
+	case *ast.MemberExpression:
+		result, _ := interp.evalChain(node, env)
+		return result
+	case *ast.CoalesceExpression:
+		return interp.evalCoalesceExpression(node, env)
+	case *ast.ConditionalExpression:
+		return interp.evalConditionalExpression(node, env)
+	case *ast.StructLiteral:
+		return interp.evalStructLiteral(node, env)
+	case *ast.AssignExpression:
+		return interp.evalAssignExpression(node, env)
+	case *ast.MatchExpression:
+		return interp.evalMatchExpression(node, env)
+	case *ast.SwitchExpression:
+		return interp.evalSwitchExpression(node, env)
+	case *ast.RangeExpression:
+		return interp.evalRangeExpression(node, env)
+	case *ast.ForExpression:
+		return interp.evalForExpression(node, env)
+	case *ast.PipeExpression:
+		return interp.evalPipeExpression(node, env)
+	case *ast.YieldExpression:
+		return interp.evalYieldExpression(node, env)
+	case *ast.TryExpression:
+		return interp.evalTryExpression(node, env)
+	case *ast.ThrowExpression:
+		return interp.evalThrowExpression(node, env)
+	case *ast.PropagateExpression:
+		return interp.evalPropagateExpression(node, env)
+	case *ast.SpreadExpression:
+		return newError("unexpected spread: %s", node.String())
 	}
 
 	return nil
@@ -63,27 +157,25 @@ func Eval(node ast.Node) object.Object {
 // ===================
 // Helper Functions
 // ===================
-func evalProgram(stmts []ast.Statement) object.Object {
+func (interp *Interpreter) evalProgram(stmts []ast.Statement, env *object.Environment) object.Object {
 	var result object.Object
 
 	for _, statement := range stmts {
-		result = Eval(statement)
+		result = interp.eval(statement, env)
 
 		switch result := result.(type) {
-		case *object.ReturnValue:
-			return result.Value
-		case *object.Error:
-			return result
+		case *object.ReturnValue, *object.Error:
+			return unwrapReturnValue(result)
 		}
 	}
 	return result
 }
 
-func evalBlockStatements(stmts []ast.Statement) object.Object {
+func (interp *Interpreter) evalBlockStatements(stmts []ast.Statement, env *object.Environment) object.Object {
 	var result object.Object
 
 	for _, statement := range stmts {
-		result = Eval(statement)
+		result = interp.eval(statement, env)
 
 		if result != nil {
 			rt := result.Type()
@@ -102,14 +194,14 @@ func nativeBoolToBooleanObject(input bool) *object.Boolean {
 	return FALSE
 }
 
-func evalPrefixExpression(operator string, right object.Object) object.Object {
+func (interp *Interpreter) evalPrefixExpression(operator string, right object.Object) object.Object {
 	switch operator {
 	case "!":
 		return evalBangOperatorExpression(right)
 	case "-":
-		return evalMinusPrefixOperatorExpression(right)
+		return interp.evalMinusPrefixOperatorExpression(right)
 	default:
-		return newError("unknown operator: %s%s", operator, right.Type())
+		return newTypeError("unknown operator: %s%s", operator, right.Type())
 	}
 }
 
@@ -126,40 +218,213 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 	}
 }
 
-func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
-	integer, ok := right.(*object.Integer)
-	if !ok {
-		return newError("unknown operator: -%s", right.Type())
+func (interp *Interpreter) evalMinusPrefixOperatorExpression(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		if right.Value == math.MinInt64 {
+			if interp.checkedArithmetic {
+				return newError("integer overflow: -(%d)", right.Value)
+			}
+			return normalizeBigInt(new(big.Int).Neg(big.NewInt(right.Value)))
+		}
+		return &object.Integer{Value: -right.Value}
+	case *object.BigInt:
+		return normalizeBigInt(new(big.Int).Neg(right.Value))
+	case *object.Decimal:
+		return right.Neg()
+	default:
+		return newTypeError("unknown operator: -%s", right.Type())
 	}
-	return &object.Integer{Value: -integer.Value}
 }
 
-func evalInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+func (interp *Interpreter) evalInfixExpression(operator string, left object.Object, right object.Object) object.Object {
 	switch {
+	case operator == ">>":
+		return interp.evalComposition(left, right)
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
-		return evalIntegerInfixExpression(operator, left, right)
+		return interp.evalIntegerInfixExpression(operator, left, right)
+	case isInteger(left) && isInteger(right):
+		return evalBigIntegerInfixExpression(operator, left, right)
+	case isNumeric(left) && isNumeric(right):
+		return interp.evalDecimalInfixExpression(operator, left, right)
+	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
+		return evalStringInfixExpression(operator, left, right)
 	case left.Type() == object.BOOLEAN_OBJ && right.Type() == object.BOOLEAN_OBJ:
 		return evalBooleanInfixExpression(operator, left, right)
+	case left.Type() == object.STRUCT_OBJ && right.Type() == object.STRUCT_OBJ,
+		left.Type() == object.INSTANCE_OBJ && right.Type() == object.INSTANCE_OBJ,
+		left.Type() == object.ENUM_VALUE_OBJ && right.Type() == object.ENUM_VALUE_OBJ:
+		return evalEqualityInfixExpression(operator, left, right)
 	case left.Type() != right.Type():
-		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
-func evalIntegerInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+func (interp *Interpreter) evalIntegerInfixExpression(operator string, left object.Object, right object.Object) object.Object {
 	leftVal := left.(*object.Integer).Value
 	rightVal := right.(*object.Integer).Value
 
+	switch operator {
+	case "+", "-", "*", "/":
+		return interp.evalIntegerArithmetic(operator, leftVal, rightVal)
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func (interp *Interpreter) evalIntegerArithmetic(operator string, leftVal int64, rightVal int64) object.Object {
+	var result int64
+	var overflow bool
+
 	switch operator {
 	case "+":
-		return &object.Integer{Value: leftVal + rightVal}
+		result = leftVal + rightVal
+		overflow = (leftVal > 0 && rightVal > 0 && result < 0) || (leftVal < 0 && rightVal < 0 && result >= 0)
 	case "-":
-		return &object.Integer{Value: leftVal - rightVal}
+		result = leftVal - rightVal
+		overflow = (leftVal >= 0 && rightVal < 0 && result < 0) || (leftVal < 0 && rightVal > 0 && result >= 0)
 	case "*":
-		return &object.Integer{Value: leftVal * rightVal}
+		result = leftVal * rightVal
+		overflow = leftVal != 0 && (result/leftVal != rightVal || (leftVal == -1 && rightVal == math.MinInt64))
 	case "/":
-		return &object.Integer{Value: leftVal / rightVal}
+		if rightVal == 0 {
+			return newError("division by zero: %d / %d", leftVal, rightVal)
+		}
+		result = leftVal / rightVal
+		overflow = leftVal == math.MinInt64 && rightVal == -1
+	}
+
+	if overflow {
+		if interp.checkedArithmetic {
+			return newError("integer overflow: %d %s %d", leftVal, operator, rightVal)
+		}
+		return evalBigIntegerArithmetic(operator, big.NewInt(leftVal), big.NewInt(rightVal))
+	}
+	return &object.Integer{Value: result}
+}
+
+func evalBigIntegerInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+	leftVal := toBigInt(left)
+	rightVal := toBigInt(right)
+
+	switch operator {
+	case "+", "-", "*", "/":
+		return evalBigIntegerArithmetic(operator, leftVal, rightVal)
+	case "<":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) > 0)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) != 0)
+	default:
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalBigIntegerArithmetic(operator string, leftVal *big.Int, rightVal *big.Int) object.Object {
+	result := new(big.Int)
+
+	switch operator {
+	case "+":
+		result.Add(leftVal, rightVal)
+	case "-":
+		result.Sub(leftVal, rightVal)
+	case "*":
+		result.Mul(leftVal, rightVal)
+	case "/":
+		if rightVal.Sign() == 0 {
+			return newError("division by zero: %s / %s", leftVal, rightVal)
+		}
+		// Quo truncates towards zero, matching int64 division
+		result.Quo(leftVal, rightVal)
+	}
+
+	return normalizeBigInt(result)
+}
+
+// Demotes a big integer back to an Integer whenever it fits into an int64
+func normalizeBigInt(value *big.Int) object.Object {
+	return object.NewInteger(value)
+}
+
+func toBigInt(obj object.Object) *big.Int {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return big.NewInt(obj.Value)
+	case *object.BigInt:
+		return obj.Value
+	default:
+		return nil
+	}
+}
+
+func isInteger(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.BIGINT_OBJ
+}
+
+func isNumeric(obj object.Object) bool {
+	return isInteger(obj) || obj.Type() == object.DECIMAL_OBJ
+}
+
+// Evaluates arithmetic where at least one side is a Decimal. Integers are treated as
+// decimals with a scale of zero, so the result is always a Decimal.
+func (interp *Interpreter) evalDecimalInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+	leftVal := toDecimal(left)
+	rightVal := toDecimal(right)
+
+	switch operator {
+	case "+":
+		return leftVal.Add(rightVal)
+	case "-":
+		return leftVal.Sub(rightVal)
+	case "*":
+		return leftVal.Mul(rightVal)
+	case "/":
+		if rightVal.Sign() == 0 {
+			return newError("division by zero: %s / %s", leftVal.Inspect(), rightVal.Inspect())
+		}
+		minScale := max(leftVal.Scale, rightVal.Scale)
+		scale := max(minScale, interp.decimalDivisionScale)
+		return leftVal.Quo(rightVal, scale, object.RoundHalfEven).Trim(minScale)
+	case "<":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) > 0)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) != 0)
+	default:
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func toDecimal(obj object.Object) *object.Decimal {
+	if decimal, ok := obj.(*object.Decimal); ok {
+		return decimal
+	}
+	return object.NewDecimal(toBigInt(obj), 0)
+}
+
+func evalStringInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+	leftVal := left.(*object.String).Value
+	rightVal := right.(*object.String).Value
+
+	switch operator {
+	case "+":
+		return &object.String{Value: leftVal + rightVal}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
@@ -169,7 +434,7 @@ func evalIntegerInfixExpression(operator string, left object.Object, right objec
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
@@ -182,27 +447,510 @@ func evalBooleanInfixExpression(operator string, left object.Object, right objec
 	case "!=":
 		return nativeBoolToBooleanObject(left != right)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalEqualityInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+	switch operator {
+	case "==":
+		return nativeBoolToBooleanObject(objectsEqual(left, right))
+	case "!=":
+		return nativeBoolToBooleanObject(!objectsEqual(left, right))
+	default:
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
-func evalIfExpression(exp *ast.IfExpression) object.Object {
-	condition := Eval(exp.Condition)
+// Compares two values structurally, so arrays, hashes and structs are equal when their contents are
+func objectsEqual(left object.Object, right object.Object) bool {
+	if isNumeric(left) && isNumeric(right) {
+		return toDecimal(left).Cmp(toDecimal(right)) == 0
+	}
+	if left.Type() != right.Type() {
+		return false
+	}
+
+	switch left := left.(type) {
+	case *object.String:
+		return left.Value == right.(*object.String).Value
+	case *object.Array:
+		right := right.(*object.Array)
+		if len(left.Elements) != len(right.Elements) {
+			return false
+		}
+		for i := range left.Elements {
+			if !objectsEqual(left.Elements[i], right.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *object.Hash:
+		right := right.(*object.Hash)
+		if len(left.Pairs) != len(right.Pairs) {
+			return false
+		}
+		for key, pair := range left.Pairs {
+			other, ok := right.Pairs[key]
+			if !ok || !objectsEqual(pair.Value, other.Value) {
+				return false
+			}
+		}
+		return true
+	case *object.Struct:
+		right := right.(*object.Struct)
+		if left.Definition != right.Definition {
+			return false
+		}
+		for i := range left.Values {
+			if !objectsEqual(left.Values[i], right.Values[i]) {
+				return false
+			}
+		}
+		return true
+	case *object.EnumValue:
+		right := right.(*object.EnumValue)
+		if left.Variant != right.Variant {
+			return false
+		}
+		for i := range left.Values {
+			if !objectsEqual(left.Values[i], right.Values[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return left == right
+	}
+}
+
+func (interp *Interpreter) evalIfExpression(exp *ast.IfExpression, env *object.Environment) object.Object {
+	condition := interp.eval(exp.Condition, env)
 	if isError(condition) {
 		return condition
 	}
 
 	if isTruthy(condition) {
-		return Eval(exp.Consequence)
+		return interp.eval(exp.Consequence, env)
 	}
 
 	if exp.Alternative != nil {
-		return Eval(exp.Alternative)
+		return interp.eval(exp.Alternative, env)
 	}
 
 	return NULL
 }
 
+// Strings are interpolated as they are, and every other value as it is inspected
+func (interp *Interpreter) evalTemplateLiteral(node *ast.TemplateLiteral, env *object.Environment) object.Object {
+	var out strings.Builder
+
+	for _, part := range node.Parts {
+		value := interp.eval(part, env)
+		if isError(value) {
+			return value
+		}
+
+		if str, ok := value.(*object.String); ok {
+			out.WriteString(str.Value)
+		} else {
+			out.WriteString(value.Inspect())
+		}
+	}
+
+	return interp.track(&object.String{Value: out.String()})
+}
+
+func (interp *Interpreter) evalConditionalExpression(exp *ast.ConditionalExpression, env *object.Environment) object.Object {
+	condition := interp.eval(exp.Condition, env)
+	if isError(condition) {
+		return condition
+	}
+
+	if isTruthy(condition) {
+		return interp.eval(exp.Consequence, env)
+	}
+	return interp.eval(exp.Alternative, env)
+}
+
+func (interp *Interpreter) evalWhileExpression(exp *ast.WhileExpression, env *object.Environment) object.Object {
+	for {
+		if err := interp.checkContext(); err != nil {
+			return err
+		}
+
+		condition := interp.eval(exp.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if !isTruthy(condition) {
+			return NULL
+		}
+
+		result := interp.eval(exp.Body, env)
+		if result != nil {
+			rt := result.Type()
+			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+				return result
+			}
+		}
+	}
+}
+
+func evalIndexExpression(left object.Object, index object.Object) object.Object {
+	switch {
+	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.HASH_OBJ:
+		return evalHashIndexExpression(left, index)
+	case left.Type() == object.HOST_OBJ && index.Type() == object.STRING_OBJ:
+		return evalHostMember(left.(*object.HostObject), index.(*object.String).Value)
+	default:
+		return newError("index operator not supported: %s", left.Type())
+	}
+}
+
+func evalArrayIndexExpression(array object.Object, index object.Object) object.Object {
+	arrayObject := array.(*object.Array)
+	idx := index.(*object.Integer).Value
+	max := int64(len(arrayObject.Elements) - 1)
+
+	if idx < 0 || idx > max {
+		return NULL
+	}
+
+	return arrayObject.Elements[idx]
+}
+
+func evalHashIndexExpression(hash object.Object, index object.Object) object.Object {
+	hashObject := hash.(*object.Hash)
+
+	key, ok := index.(object.Hashable)
+	if !ok {
+		return newError("unusable as hash key: %s", index.Type())
+	}
+
+	value, ok := hashObject.Get(key)
+	if !ok {
+		return NULL
+	}
+
+	return value
+}
+
+func evalHostMember(host *object.HostObject, name string) object.Object {
+	member, err := host.GetMember(name)
+	if err != nil {
+		return &object.Error{Message: err.Error(), Cause: err}
+	}
+	return member
+}
+
+func (interp *Interpreter) evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
+	hash := object.NewHash()
+
+	for _, keyNode := range node.Keys {
+		key := interp.eval(keyNode, env)
+		if isError(key) {
+			return key
+		}
+
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", key.Type())
+		}
+
+		value := interp.eval(node.Pairs[keyNode], env)
+		if isError(value) {
+			return value
+		}
+
+		hash.Set(hashKey, value)
+	}
+
+	return interp.track(hash)
+}
+
+func (interp *Interpreter) evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object {
+	if val, ok := env.Get(node.Value); ok {
+		return val
+	}
+
+	if builtin, ok := interp.builtins[node.Value]; ok {
+		return builtin
+	}
+
+	return &object.Error{Message: "identifier not found: " + node.Value, Kind: "NameError"}
+}
+
+// Evaluates a list of expressions, expanding any spread arrays in place
+func (interp *Interpreter) evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
+	var result []object.Object
+
+	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadExpression); ok {
+			evaluated := interp.eval(spread.Value, env)
+			if isError(evaluated) {
+				return []object.Object{evaluated}
+			}
+			elements, err := interp.spreadElements(evaluated)
+			if err != nil {
+				return []object.Object{err}
+			}
+			result = append(result, elements...)
+			continue
+		}
+
+		evaluated := interp.eval(e, env)
+		if isError(evaluated) {
+			return []object.Object{evaluated}
+		}
+		result = append(result, evaluated)
+	}
+
+	return result
+}
+
+// Expands an array in place, or any other sequence once its elements are collected
+func (interp *Interpreter) spreadElements(obj object.Object) ([]object.Object, *object.Error) {
+	if array, ok := obj.(*object.Array); ok {
+		return array.Elements, nil
+	}
+	if _, err := interp.iterable(obj); err != nil {
+		return nil, newError("cannot spread %s", obj.Type())
+	}
+	return interp.collect(obj)
+}
+
+// Evaluates the arguments of a call, separating the named arguments that follow the positional ones
+func (interp *Interpreter) evalArguments(exps []ast.Expression, env *object.Environment) ([]object.Object, []namedArgument, object.Object) {
+	positional := exps
+	for i, exp := range exps {
+		if _, ok := exp.(*ast.NamedArgument); ok {
+			positional = exps[:i]
+			break
+		}
+	}
+
+	args := interp.evalExpressions(positional, env)
+	if len(args) == 1 && isError(args[0]) {
+		return nil, nil, args[0]
+	}
+
+	var named []namedArgument
+	for _, exp := range exps[len(positional):] {
+		arg := exp.(*ast.NamedArgument)
+		value := interp.eval(arg.Value, env)
+		if isError(value) {
+			return nil, nil, value
+		}
+		named = append(named, namedArgument{name: arg.Name.Value, value: value})
+	}
+
+	return args, named, nil
+}
+
+func (interp *Interpreter) applyFunction(fn object.Object, args []object.Object) object.Object {
+	return interp.apply(fn, args, nil)
+}
+
+// A named argument of a call, such as y in f(y: 2)
+type namedArgument struct {
+	name  string
+	value object.Object
+}
+
+// Calls fn with positional arguments followed by named ones
+func (interp *Interpreter) apply(fn object.Object, args []object.Object, named []namedArgument) object.Object {
+	if err := interp.checkContext(); err != nil {
+		return err
+	}
+
+	if interp.limits.MaxCallDepth > 0 && interp.depth >= interp.limits.MaxCallDepth {
+		return newLimitError("maximum call depth of %d exceeded", interp.limits.MaxCallDepth)
+	}
+	// Not deferred, as a generator abandoned while it is suspended ends its goroutine
+	// with runtime.Goexit, which must not touch the interpreter
+	interp.depth++
+	result := interp.call(fn, args, named)
+	interp.depth--
+
+	return result
+}
+
+func (interp *Interpreter) call(fn object.Object, args []object.Object, named []namedArgument) object.Object {
+	if interp.hooks.OnCall != nil {
+		interp.hooks.OnCall(fn, args)
+	}
+
+	switch fn := fn.(type) {
+	case *object.Function:
+		return interp.callFunction(fn, nil, args, named)
+	case *object.BoundMethod:
+		return interp.callFunction(fn.Method, fn.Receiver, args, named)
+	case *object.Class:
+		return interp.instantiate(fn, args, named)
+	}
+
+	// Everything else callable only takes positional arguments
+	if len(named) > 0 {
+		switch fn.(type) {
+		case *object.EnumVariant, *object.Builtin, *object.StructType:
+			return newError("named arguments are not supported by %s", fn.Type())
+		}
+	}
+
+	switch fn := fn.(type) {
+	case *object.EnumVariant:
+		return interp.constructVariant(fn, args)
+	case *object.Builtin:
+		return interp.track(fn.Fn(args...))
+	case *object.StructType:
+		if len(args) != len(fn.Fields) {
+			return newError("wrong number of arguments. got=%d, want=%d", len(args), len(fn.Fields))
+		}
+		return interp.newStruct(fn, append([]object.Object{}, args...))
+	default:
+		return newError("not a function: %s", fn.Type())
+	}
+}
+
+// Calls fn in a new environment. For methods, self is the receiver and fills the first parameter.
+func (interp *Interpreter) callFunction(fn *object.Function, self object.Object, args []object.Object, named []namedArgument) object.Object {
+	if err := interp.allocate(environmentSize); err != nil {
+		return err
+	}
+
+	extendedEnv, err := interp.bindArguments(fn, self, args, named)
+	if err != nil {
+		return err
+	}
+
+	if fn.Generator {
+		return interp.newGenerator(fn.Body, extendedEnv)
+	}
+
+	evaluated := unwrapReturnValue(interp.eval(fn.Body, extendedEnv))
+	if err, ok := evaluated.(*object.Error); ok {
+		err.Stack = append(err.Stack, functionName(fn))
+	}
+	return evaluated
+}
+
+// Binds the arguments of a call to the parameters of fn. Parameters left without an
+// argument take their default value, which can refer to the parameters before it, and
+// arguments beyond the last parameter are collected by the rest parameter.
+func (interp *Interpreter) bindArguments(fn *object.Function, self object.Object, args []object.Object, named []namedArgument) (*object.Environment, *object.Error) {
+	offset := 0
+	if self != nil {
+		args = append([]object.Object{self}, args...)
+		offset = 1
+	}
+
+	var surplus []object.Object
+	if len(args) > len(fn.Parameters) {
+		if fn.Rest == nil {
+			return nil, arityError(fn, len(args)-offset, offset)
+		}
+		surplus = append(surplus, args[len(fn.Parameters):]...)
+		args = args[:len(fn.Parameters)]
+	}
+
+	values := make([]object.Object, len(fn.Parameters))
+	copy(values, args)
+
+	for _, arg := range named {
+		idx := parameterIndex(fn, arg.name)
+		if idx < offset {
+			return nil, newError("unknown parameter %s", arg.name)
+		}
+		if values[idx] != nil {
+			return nil, newError("multiple values for parameter %s", arg.name)
+		}
+		values[idx] = arg.value
+	}
+
+	env := object.NewEnclosedEnvironment(fn.Env)
+
+	for i, param := range fn.Parameters {
+		value := values[i]
+		if value == nil {
+			if fn.Defaults == nil || fn.Defaults[i] == nil {
+				if len(named) == 0 {
+					return nil, arityError(fn, len(args)-offset, offset)
+				}
+				return nil, newError("missing argument for parameter %s", param.Value)
+			}
+
+			value = interp.eval(fn.Defaults[i], env)
+			if err, ok := value.(*object.Error); ok {
+				return nil, err
+			}
+		}
+
+		if fn.Patterns != nil && fn.Patterns[i] != nil {
+			if err := interp.destructure(fn.Patterns[i], value, env); err != nil {
+				return nil, err
+			}
+		} else {
+			env.Set(param.Value, value)
+		}
+	}
+
+	if fn.Rest != nil {
+		rest := interp.track(&object.Array{Elements: surplus})
+		if err, ok := rest.(*object.Error); ok {
+			return nil, err
+		}
+		env.Set(fn.Rest.Value, rest)
+	}
+
+	return env, nil
+}
+
+func parameterIndex(fn *object.Function, name string) int {
+	for i, param := range fn.Parameters {
+		if param.Value == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Describes how many arguments fn takes, leaving out the receiver of a method
+func arityError(fn *object.Function, got int, offset int) *object.Error {
+	total := len(fn.Parameters) - offset
+	required := total
+	for _, def := range fn.Defaults {
+		if def != nil {
+			required--
+		}
+	}
+
+	want := fmt.Sprintf("%d", required)
+	switch {
+	case fn.Rest != nil:
+		want = fmt.Sprintf("at least %d", required)
+	case required != total:
+		want = fmt.Sprintf("%d to %d", required, total)
+	}
+
+	return newError("wrong number of arguments. got=%d, want=%s", got, want)
+}
+
+func unwrapReturnValue(obj object.Object) object.Object {
+	switch obj := obj.(type) {
+	case *object.ReturnValue:
+		return obj.Value
+	case *object.Error:
+		if obj.Return != nil {
+			return obj.Return
+		}
+	}
+
+	return obj
+}
+
 func isTruthy(obj object.Object) bool {
 	switch obj {
 	case NULL:
@@ -220,6 +968,11 @@ func newError(format string, a ...interface{}) *object.Error {
 	return &object.Error{Message: fmt.Sprintf(format, a...)}
 }
 
+// Errors for operations applied to values of the wrong type
+func newTypeError(format string, a ...interface{}) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...), Kind: "TypeError"}
+}
+
 func isError(obj object.Object) bool {
 	if obj != nil {
 		return obj.Type() == object.ERROR_OBJ
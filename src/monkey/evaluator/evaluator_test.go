@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"context"
 	"monkey/lexer"
 	"monkey/object"
 	"monkey/parser"
@@ -38,6 +39,147 @@ func TestEvalIntegerExpression(t *testing.T) {
 	}
 }
 
+func TestBigIntegerArithmetic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775807 - 2", "-9223372036854775809"},
+		{"4611686018427387904 * 4", "18446744073709551616"},
+		{"(-9223372036854775807 - 1) / -1", "9223372036854775808"},
+		{"-(-9223372036854775807 - 1)", "9223372036854775808"},
+		{"99999999999999999999", "99999999999999999999"},
+		{"-99999999999999999999", "-99999999999999999999"},
+		{"99999999999999999999 * 99999999999999999999", "9999999999999999999800000000000000000001"},
+		{"100000000000000000000 / 3", "33333333333333333333"},
+		{"-100000000000000000000 / 3", "-33333333333333333333"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		result, ok := evaluated.(*object.BigInt)
+		if !ok {
+			t.Errorf("object is not BigInt. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if result.Inspect() != tt.expected {
+			t.Errorf("object has wrong value. got=%s, want=%s", result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestBigIntegerDemotion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"9223372036854775807 + 1 - 1", 9223372036854775807},
+		{"99999999999999999999 - 99999999999999999990", 9},
+		{"99999999999999999999 / 99999999999999999999", 1},
+		{"-9223372036854775808", -9223372036854775808},
+		{"18446744073709551616 / 4611686018427387904", 4},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testIntegerObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"12.50d", "12.50"},
+		{"0.05d", "0.05"},
+		{"-0.05d", "-0.05"},
+		{"7d", "7"},
+		{"0.1d + 0.2d", "0.3"},
+		{"12.50d + 0.125d", "12.625"},
+		{"10.00d - 0.01d", "9.99"},
+		{"1.10d * 1.10d", "1.2100"},
+		{"19.99d * 3", "59.97"},
+		{"3 * 19.99d", "59.97"},
+		{"10 - 0.01d", "9.99"},
+		{"99999999999999999999 + 0.5d", "99999999999999999999.5"},
+		{"10.00d / 4", "2.50"},
+		{"10d / 4", "2.5"},
+		{"1d / 3", "0.3333333333333333"},
+		{"2d / 3", "0.6666666666666667"},
+		{"-2d / 3", "-0.6666666666666667"},
+		{"100.00d / 3", "33.3333333333333333"},
+		{"round(2.345d, 2)", "2.34"},
+		{"round(2.355d, 2)", "2.36"},
+		{"round(2.345d, 2, \"half_even\")", "2.34"},
+		{"round(2.345d, 2, \"half_up\")", "2.35"},
+		{"round(2.349d, 2, \"down\")", "2.34"},
+		{"round(-2.345d, 2, \"half_up\")", "-2.35"},
+		{"round(-2.349d, 2, \"down\")", "-2.34"},
+		{"round(2.5d, 0)", "2"},
+		{"round(3.5d, 0)", "4"},
+		{"round(1.5d, 4)", "1.5000"},
+		{"round(5, 2)", "5.00"},
+		{"round(100d / 3, 2, \"half_up\")", "33.33"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		result, ok := evaluated.(*object.Decimal)
+		if !ok {
+			t.Errorf("object is not Decimal. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if result.Inspect() != tt.expected {
+			t.Errorf("object has wrong value for %q. got=%s, want=%s", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestStringConcatenation(t *testing.T) {
+	evaluated := testEval(`"Hello" + " " + "World!"`)
+
+	str, ok := evaluated.(*object.String)
+	if !ok {
+		t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if str.Value != "Hello World!" {
+		t.Errorf("String has wrong value. got=%q", str.Value)
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let n = 3; "${n} items"`, "3 items"},
+		{`let name = "ann"; "hello ${name}!"`, "hello ann!"},
+		{`"${1 + 2}${"x"}"`, "3x"},
+		{`let h = {"k": [1, 2]}; "k=${h["k"]}"`, "k=[1, 2]"},
+		{`let h = {"k": 1}; "${ {"a": h["k"]}["a"] }"`, "1"},
+		{`"${true} ${12.50d} ${fn(x) { x }(7)}"`, "true 12.50 7"},
+		{`"nested ${"inner ${1 + 1}"}"`, "nested inner 2"},
+		{`let f = fn(x) { "<${x}>" }; f("a") + f(1)`, "<a><1>"},
+		{`"$ {not} $x {y}"`, "$ {not} $x {y}"},
+		{`let n = 1; "\${n} is ${n}"`, "${n} is 1"},
+		{`"say \"hi\""`, `say "hi"`},
+		{`let n = 1; "\"${n}\" \\ C:\dir"`, `"1" \ C:\dir`},
+		{`"${"\"quoted\""}"`, `"quoted"`},
+		{"`raw ${x} \"quoted\"`", "raw ${x} \"quoted\""},
+		{"`line one\nline two`", "line one\nline two"},
+		{`"${missing}"`, errorMessage("identifier not found: missing")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
 func TestEvalBooleanExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -62,6 +204,20 @@ func TestEvalBooleanExpression(t *testing.T) {
 		{"(1 < 2) == false", false},
 		{"(1 > 2) == true", false},
 		{"(1 > 2) == false", true},
+		{"99999999999999999999 > 1", true},
+		{"99999999999999999999 < 1", false},
+		{"-99999999999999999999 < 1", true},
+		{"99999999999999999999 == 99999999999999999999", true},
+		{"99999999999999999999 != 99999999999999999999", false},
+		{"9223372036854775807 + 1 == 9223372036854775808", true},
+		{"1.50d == 1.5d", true},
+		{"1.50d != 1.5d", false},
+		{"0.1d + 0.2d == 0.3d", true},
+		{"2 == 2.00d", true},
+		{"1.99d < 2", true},
+		{"-1.99d > -2", true},
+		{`"a" == "a"`, true},
+		{`"a" != "b"`, true},
 	}
 
 	for _, tt := range tests {
@@ -114,6 +270,47 @@ func TestIfElseExpressions(t *testing.T) {
 	}
 }
 
+func TestConditionalExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"true ? 1 : 2", 1},
+		{"false ? 1 : 2", 2},
+		{"1 < 2 ? 10 : 20", 10},
+		{"let x = 5; x > 3 ? x > 4 ? 2 : 1 : 0", 2},
+		{"let x = 1; x > 3 ? 2 : x > 0 ? 1 : 0", 1},
+		{"let h = {}; h[\"a\"] ?? false ? \"set\" : \"unset\"", "unset"},
+		{"true ? 1 : missing", 1},
+		{"missing ? 1 : 2", errorMessage("identifier not found: missing")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestWhileExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"while (false) { 10 }", nil},
+		{"let i = 0; while (i < 5) { let i = i + 1; }; i", 5},
+		{"let f = fn() { let i = 0; while (true) { if (i > 2) { return i; } let i = i + 1; } }; f()", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -189,6 +386,78 @@ if (10 > 1) {
 `,
 			"unknown operator: BOOLEAN + BOOLEAN",
 		},
+		{
+			"10 / 0",
+			"division by zero: 10 / 0",
+		},
+		{
+			"5 + 10 / (2 - 2); 5",
+			"division by zero: 10 / 0",
+		},
+		{
+			"99999999999999999999 / 0",
+			"division by zero: 99999999999999999999 / 0",
+		},
+		{
+			"99999999999999999999 + true",
+			"type mismatch: BIGINT + BOOLEAN",
+		},
+		{
+			"1.00d / 0",
+			"division by zero: 1.00 / 0",
+		},
+		{
+			"1.00d + true",
+			"type mismatch: DECIMAL + BOOLEAN",
+		},
+		{
+			`"Hello" - "World"`,
+			"unknown operator: STRING - STRING",
+		},
+		{
+			"foobar",
+			"identifier not found: foobar",
+		},
+		{
+			"let add = fn(x, y) { x + y }; add(1)",
+			"wrong number of arguments. got=1, want=2",
+		},
+		{
+			"let x = 5; x(1)",
+			"not a function: INTEGER",
+		},
+		{
+			`{"name": "Monkey"}[fn(x) { x }];`,
+			"unusable as hash key: FUNCTION",
+		},
+		{
+			`{[1]: 2}`,
+			"unusable as hash key: ARRAY",
+		},
+		{
+			`1[0]`,
+			"index operator not supported: INTEGER",
+		},
+		{
+			"round(1.00d)",
+			"wrong number of arguments. got=1, want=2 or 3",
+		},
+		{
+			"round(true, 2)",
+			"argument to `round` must be DECIMAL or INTEGER, got BOOLEAN",
+		},
+		{
+			"round(1.00d, 2, \"up\")",
+			"unknown rounding mode: \"up\"",
+		},
+		{
+			"round(1.00d, -1)",
+			"places passed to `round` must not be negative, got -1",
+		},
+		{
+			"round(1d, 1000000000)",
+			"places passed to `round` must be at most 10000, got 1000000000",
+		},
 	}
 
 	for _, tt := range tests {
@@ -208,15 +477,334 @@ if (10 > 1) {
 	}
 }
 
+func TestCheckedArithmetic(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{
+			"9223372036854775807 + 1",
+			"integer overflow: 9223372036854775807 + 1",
+		},
+		{
+			"-9223372036854775807 - 2",
+			"integer overflow: -9223372036854775807 - 2",
+		},
+		{
+			"4611686018427387904 * 2",
+			"integer overflow: 4611686018427387904 * 2",
+		},
+		{
+			"(-9223372036854775807 - 1) / -1",
+			"integer overflow: -9223372036854775808 / -1",
+		},
+		{
+			"-(-9223372036854775807 - 1)",
+			"integer overflow: -(-9223372036854775808)",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input, WithCheckedArithmetic(true))
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)",
+				evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expectedMessage, errObj.Message)
+		}
+	}
+
+	testIntegerObject(t, testEval("9223372036854775806 + 1", WithCheckedArithmetic(true)), 9223372036854775807)
+}
+
+func TestLetStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let a = 5; a;", 5},
+		{"let a = 5 * 5; a;", 25},
+		{"let a = 5; let b = a; b;", 5},
+		{"let a = 5; let b = a; let c = a + b + 5; c;", 15},
+		{"let a = 5; a", 5},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFunctionObject(t *testing.T) {
+	input := "fn(x) { x + 2; };"
+
+	evaluated := testEval(input)
+	fn, ok := evaluated.(*object.Function)
+	if !ok {
+		t.Fatalf("object is not Function. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if len(fn.Parameters) != 1 {
+		t.Fatalf("function has wrong parameters. Parameters=%+v", fn.Parameters)
+	}
+
+	if fn.Parameters[0].String() != "x" {
+		t.Fatalf("parameter is not 'x'. got=%q", fn.Parameters[0])
+	}
+
+	expectedBody := "(x + 2)"
+
+	if fn.Body.String() != expectedBody {
+		t.Fatalf("body is not %q. got=%q", expectedBody, fn.Body.String())
+	}
+}
+
+func TestFunctionApplication(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let identity = fn(x) { x; }; identity(5);", 5},
+		{"let identity = fn(x) { return x; }; identity(5);", 5},
+		{"let double = fn(x) { x * 2; }; double(5);", 10},
+		{"let add = fn(x, y) { x + y; }; add(5, 5);", 10},
+		{"let add = fn(x, y) { x + y; }; add(5 + 5, add(5, 5));", 20},
+		{"fn(x) { x; }(5)", 5},
+		{"let fact = fn(n) { if (n < 2) { return 1; } n * fact(n - 1) }; fact(5)", 120},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestClosures(t *testing.T) {
+	input := `
+let newAdder = fn(x) {
+  fn(y) { x + y };
+};
+
+let addTwo = newAdder(2);
+addTwo(2);`
+
+	testIntegerObject(t, testEval(input), 4)
+}
+
+func TestArrayLiterals(t *testing.T) {
+	evaluated := testEval("[1, 2 * 2, 3 + 3]")
+
+	result, ok := evaluated.(*object.Array)
+	if !ok {
+		t.Fatalf("object is not Array. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if len(result.Elements) != 3 {
+		t.Fatalf("array has wrong num of elements. got=%d", len(result.Elements))
+	}
+
+	testIntegerObject(t, result.Elements[0], 1)
+	testIntegerObject(t, result.Elements[1], 4)
+	testIntegerObject(t, result.Elements[2], 6)
+}
+
+func TestArrayIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[1, 2, 3][0]", 1},
+		{"[1, 2, 3][2]", 3},
+		{"let i = 0; [1][i];", 1},
+		{"[1, 2, 3][1 + 1];", 3},
+		{"let myArray = [1, 2, 3]; myArray[0] + myArray[1] + myArray[2];", 6},
+		{"[1, 2, 3][3]", nil},
+		{"[1, 2, 3][-1]", nil},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestHashLiterals(t *testing.T) {
+	input := `let two = "two";
+	{
+		"one": 10 - 9,
+		two: 1 + 1,
+		"thr" + "ee": 6 / 2,
+		4: 4,
+		true: 5,
+		false: 6
+	}`
+
+	evaluated := testEval(input)
+	result, ok := evaluated.(*object.Hash)
+	if !ok {
+		t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if result.Inspect() != `{one: 1, two: 2, three: 3, 4: 4, true: 5, false: 6}` {
+		t.Errorf("hash has wrong contents. got=%s", result.Inspect())
+	}
+}
+
+func TestHashIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{"foo": 5}["foo"]`, 5},
+		{`{"foo": 5}["bar"]`, nil},
+		{`let key = "foo"; {"foo": 5}[key]`, 5},
+		{`{}["foo"]`, nil},
+		{`{5: 5}[5]`, 5},
+		{`{true: 5}[true]`, 5},
+		{`{false: 5}[false]`, 5},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`len("")`, 0},
+		{`len("four")`, 4},
+		{`len("héllo")`, 5},
+		{`len([1, 2, 3])`, 3},
+		{`len({"a": 1})`, 1},
+		{`len(1)`, "argument to `len` not supported, got INTEGER"},
+		{`len("one", "two")`, "wrong number of arguments. got=2, want=1"},
+		{`first([1, 2, 3])`, 1},
+		{`first([])`, nil},
+		{`last([1, 2, 3])`, 3},
+		{`last([])`, nil},
+		{`rest([1, 2, 3])`, []int{2, 3}},
+		{`rest([])`, nil},
+		{`push([], 1)`, []int{1}},
+		{`push(1, 1)`, "argument to `push` must be ARRAY, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case nil:
+			testNullObject(t, evaluated)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		case []int:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("obj not Array. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if len(array.Elements) != len(expected) {
+				t.Errorf("wrong num of elements. want=%d, got=%d", len(expected), len(array.Elements))
+				continue
+			}
+			for i, expectedElem := range expected {
+				testIntegerObject(t, array.Elements[i], int64(expectedElem))
+			}
+		}
+	}
+}
+
+func TestMemberExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let h = {"name": "monkey", "age": 3}; h.age`, 3},
+		{`let h = {"name": "monkey"}; h.name == h["name"]`, true},
+		{`let h = {"name": "monkey"}; h.missing`, nil},
+		{`let h = {"inc": fn(x) { x + 1 }}; h.inc(1)`, 2},
+		{`let h = {"len": 10}; h.len`, 10},
+		{`{"a": 1, "b": 2}.len()`, 2},
+		{`"monkey".upper()`, "MONKEY"},
+		{`"MoNkEy".lower()`, "monkey"},
+		{`"monkey".len()`, 6},
+		{`"héllo".len()`, 5},
+		{`let s = "héllo"; s[..s.len() - 1]`, "héll"},
+		{`let upper = "abc".upper; upper()`, "ABC"},
+		{`[1, 2, 3].map(fn(x) { x * 2 }).len()`, 3},
+		{`[1, 2, 3].map(fn(x) { x * 2 })[2]`, 6},
+		{`[1, 2, 3].map(len)`, errorMessage("argument to `len` not supported, got INTEGER")},
+		{`"monkey".shout()`, errorMessage("unknown method: STRING.shout")},
+		{`5.len()`, errorMessage("unknown method: INTEGER.len")},
+		{`"monkey".upper(1)`, errorMessage("wrong number of arguments. got=1, want=0")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
 // ===================
 // Helper Functions
 // ===================
-func testEval(input string) object.Object {
+func testExpectedObject(t *testing.T, obj object.Object, expected interface{}) {
+	t.Helper()
+
+	switch expected := expected.(type) {
+	case int:
+		testIntegerObject(t, obj, int64(expected))
+	case bool:
+		testBooleanObject(t, obj, expected)
+	case string:
+		testStringObject(t, obj, expected)
+	case nil:
+		testNullObject(t, obj)
+	case errorMessage:
+		errObj, ok := obj.(*object.Error)
+		if !ok {
+			t.Errorf("object is not Error. got=%T (%+v)", obj, obj)
+			return
+		}
+		if errObj.Message != string(expected) {
+			t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+		}
+	default:
+		t.Fatalf("unsupported expectation %T", expected)
+	}
+}
+
+func testEval(input string, options ...Option) object.Object {
 	lex := lexer.New(input)
 	par := parser.New(lex)
 	program := par.ParseProgram()
 
-	return Eval(program)
+	return New(options...).Eval(context.Background(), program)
 }
 
 func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
@@ -249,6 +837,21 @@ func testBooleanObject(t *testing.T, obj object.Object, expected bool) bool {
 	return true
 }
 
+func testStringObject(t *testing.T, obj object.Object, expected string) bool {
+	result, ok := obj.(*object.String)
+	if !ok {
+		t.Errorf("object is not String. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if result.Value != expected {
+		t.Errorf("object has wrong value. got=%q, want=%q",
+			result.Value, expected)
+		return false
+	}
+
+	return true
+}
+
 func testNullObject(t *testing.T, obj object.Object) bool {
 	if obj != NULL {
 		t.Errorf("object is not NULL. got=%T (%+v)", obj, obj)
@@ -0,0 +1,140 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"monkey/ast"
+	"monkey/object"
+)
+
+// The kind of errors thrown as a string, or made by error() without a kind
+const defaultErrorKind = "Error"
+
+func (interp *Interpreter) evalTryExpression(node *ast.TryExpression, env *object.Environment) object.Object {
+	result := interp.eval(node.Body, env)
+
+	if err, ok := result.(*object.Error); ok && catchable(err) && node.Catch != nil {
+		result = interp.evalCatch(node, err, env)
+	}
+
+	// Timeouts and exceeded limits end the program straight away, skipping the
+	// finally blocks as well
+	if err, ok := result.(*object.Error); ok && fatal(err) {
+		return result
+	}
+
+	if node.Finally != nil {
+		// Leaving the finally block early replaces whatever the try block produced
+		finally := interp.eval(node.Finally, env)
+		if finally != nil {
+			rt := finally.Type()
+			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+				return finally
+			}
+		}
+	}
+
+	return result
+}
+
+func (interp *Interpreter) evalCatch(node *ast.TryExpression, err *object.Error, env *object.Environment) object.Object {
+	if node.Parameter == nil {
+		return interp.eval(node.Catch, env)
+	}
+
+	if err := interp.allocate(environmentSize); err != nil {
+		return err
+	}
+	catchEnv := object.NewEnclosedEnvironment(env)
+
+	exception := interp.track(newException(err))
+	if isError(exception) {
+		return exception
+	}
+	catchEnv.Set(node.Parameter.Value, exception)
+
+	return interp.eval(node.Catch, catchEnv)
+}
+
+// Whether a try expression may catch err. Early returns made by the ? operator only
+// look like errors, and fatal errors cannot be caught.
+func catchable(err *object.Error) bool {
+	return err.Return == nil && !fatal(err)
+}
+
+// Timeouts, cancellation and exceeded limits must end the program whatever it does to
+// handle errors
+func fatal(err *object.Error) bool {
+	return errors.Is(err, ErrLimitExceeded) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+func newException(err *object.Error) *object.Exception {
+	kind := err.Kind
+	if kind == "" {
+		kind = "RuntimeError"
+	}
+	return &object.Exception{Message: err.Message, Kind: kind, Stack: append([]string{}, err.Stack...)}
+}
+
+func (interp *Interpreter) evalThrowExpression(node *ast.ThrowExpression, env *object.Environment) object.Object {
+	value := interp.eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	switch value := value.(type) {
+	case *object.String:
+		return &object.Error{Message: value.Value, Kind: defaultErrorKind}
+	case *object.Exception:
+		// Rethrowing keeps the stack of the original error
+		return &object.Error{Message: value.Message, Kind: value.Kind, Stack: append([]string{}, value.Stack...)}
+	default:
+		return newTypeError("cannot throw %s", value.Type())
+	}
+}
+
+func evalExceptionMember(exception *object.Exception, name string) object.Object {
+	switch name {
+	case "message":
+		return &object.String{Value: exception.Message}
+	case "kind":
+		return &object.String{Value: exception.Kind}
+	case "stack":
+		frames := make([]object.Object, len(exception.Stack))
+		for i, frame := range exception.Stack {
+			frames[i] = &object.String{Value: frame}
+		}
+		return &object.Array{Elements: frames}
+	default:
+		return newError("exception has no field %s", name)
+	}
+}
+
+// The name of fn in stack traces
+func functionName(fn *object.Function) string {
+	if fn.Name == "" {
+		return "<anonymous>"
+	}
+	return fn.Name
+}
+
+// error(message, kind) makes an exception to throw later, of kind "Error" unless
+// another is given
+func builtinError(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newError("wrong number of arguments. got=%d, want=1 to 2", len(args))
+	}
+
+	strArgs, err := stringArguments("error", len(args), args)
+	if err != nil {
+		return err
+	}
+
+	exception := &object.Exception{Message: strArgs[0], Kind: defaultErrorKind}
+	if len(strArgs) == 2 {
+		exception.Kind = strArgs[1]
+	}
+	return exception
+}
@@ -0,0 +1,91 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"monkey/object"
+	"testing"
+	"time"
+)
+
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`try { 1 } catch (e) { 2 }`, 1},
+		{`try { throw "bad record" } catch (e) { e.message }`, "bad record"},
+		{`try { throw "bad record" } catch (e) { e.kind }`, "Error"},
+		{`try { throw error("no such user", "LookupError") } catch (e) { e.kind + ": " + e.message }`, "LookupError: no such user"},
+		{`try { 1 + true } catch (e) { e.kind + ": " + e.message }`, "TypeError: type mismatch: INTEGER + BOOLEAN"},
+		{`try { -"a" } catch (e) { e.kind }`, "TypeError"},
+		{`try { missing } catch (e) { e.kind }`, "NameError"},
+		{`try { [1].nope() } catch (e) { e.kind }`, "RuntimeError"},
+		{`try { throw "x" } catch { "recovered" }`, "recovered"},
+		{`try { throw "x" } catch (e) { e }.message`, "x"},
+		{`let e = error("late"); try { throw e } catch (caught) { caught.message }`, "late"},
+		{`try { try { throw "inner" } catch (e) { throw e } } catch (e) { "outer " + e.message }`, "outer inner"},
+		{`try { try { throw "inner" } finally { 1 } } catch (e) { e.message }`, "inner"},
+		{`try { throw 1 } catch (e) { e.message }`, "cannot throw INTEGER"},
+		{`throw "uncaught"`, errorMessage("uncaught")},
+		{`try { throw "a" } catch (e) { throw "b" }`, errorMessage("b")},
+		{`try { 1 } catch (e) { 2 }; e`, errorMessage("identifier not found: e")},
+		{`try { throw "x" } catch (e) { e.line }`, errorMessage("exception has no field line")},
+		{`error(1)`, errorMessage("argument to `error` must be STRING, got INTEGER")},
+		{`let parse = fn(x) { if (x < 0) { throw "negative" } x }; let total = fn(xs) { xs.map(fn(x) { try { parse(x) } catch (e) { 0 } }).join(",") }; total([1, -2, 3])`, "1,0,3"},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFinally(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let f = fn() { try { 1 } finally { 2 } }; f()`, 1},
+		{`let f = fn() { try { return 1 } finally { 3 } }; f()`, 1},
+		{`let f = fn() { try { return 1 } finally { return 2 } }; f()`, 2},
+		{`let f = fn() { try { throw "x" } finally { return 2 } }; f()`, 2},
+		{`try { throw "x" } catch (e) { 1 } finally { 2 }`, 1},
+		{`try { throw "x" } finally { 2 }`, errorMessage("x")},
+		{`try { 1 } finally { throw "from finally" }`, errorMessage("from finally")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestExceptionStack(t *testing.T) {
+	input := `
+	class Parser {
+		fn parse(self, x) { check(x) }
+	}
+	let check = fn(x) { if (x < 0) { throw "negative" } x };
+	let run = fn() { Parser().parse(-1) };
+	try { run() } catch (e) { e.stack.join(" < ") }
+	`
+	testExpectedObject(t, testEval(input), "check < Parser.parse < run")
+
+	input = `try { fn() { 1 + true }() } catch (e) { e.stack.join(",") }`
+	testExpectedObject(t, testEval(input), "<anonymous>")
+}
+
+func TestLimitsAreNotCatchable(t *testing.T) {
+	interp := New(WithLimits(Limits{MaxSteps: 1000}))
+	evaluated := evalWith(interp, `try { while (true) { } } catch (e) { "caught" } finally { "cleanup" }`)
+	if err, ok := evaluated.(*object.Error); !ok || !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected a step limit error. got=%s", evaluated.Inspect())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	evaluated = evalWithContext(ctx, New(), `try { while (true) { } } catch (e) { "caught" }`)
+	if err, ok := evaluated.(*object.Error); !ok || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a timeout. got=%s", evaluated.Inspect())
+	}
+}
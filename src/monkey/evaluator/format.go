@@ -0,0 +1,391 @@
+package evaluator
+
+import (
+	"math/big"
+	"monkey/object"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// How a placeholder of format or sprintf lays out its value
+type formatSpec struct {
+	fill      rune
+	align     byte // '<', '>' or '^', or 0 to put numbers on the right and the rest on the left
+	sign      byte // '+' or ' ' to mark positive numbers, or 0 for nothing
+	zero      bool // pad numbers with zeros between the sign and the digits
+	width     int
+	precision int  // -1 if not given
+	verb      byte // 0 for the default representation
+}
+
+// The widest a placeholder may pad its value to. Wider padding would let a single
+// call build a huge string before the allocation limits could react. Precisions are
+// limited to maxPrecision, as for round.
+const maxWidth = 10000
+
+func newFormatSpec() formatSpec {
+	return formatSpec{fill: ' ', precision: -1}
+}
+
+// format(template, args...) replaces each {} in template with the next argument. A
+// placeholder can also pick an argument by position, {0}, or by name, {price}, which is
+// looked up in the last argument. After a colon it can say how to lay the value out, as
+// in {price:>10.2f}: [[fill]align][sign][0][width][.precision][verb], where align is
+// one of < > ^, and verb one of d x X o b f s q. {{ and }} stand for braces.
+func builtinFormat(args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newError("wrong number of arguments. got=%d, want=at least 1", len(args))
+	}
+	template, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `format` must be STRING, got %s", args[0].Type())
+	}
+	values := args[1:]
+
+	var out strings.Builder
+	next := 0
+	text := template.Value
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case ch == '{' && strings.HasPrefix(text[i:], "{{"):
+			out.WriteByte('{')
+			i++
+		case ch == '}' && strings.HasPrefix(text[i:], "}}"):
+			out.WriteByte('}')
+			i++
+		case ch == '}':
+			return newError("single } in format string")
+		case ch == '{':
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				return newError("unterminated placeholder in format string")
+			}
+			placeholder := text[i+1 : i+end]
+			i += end
+
+			key, layout, _ := strings.Cut(placeholder, ":")
+			value, err := placeholderValue(key, values, &next)
+			if err != nil {
+				return err
+			}
+
+			spec, err := parseFormatSpec(layout)
+			if err != nil {
+				return err
+			}
+			formatted, err := formatValue(value, spec)
+			if err != nil {
+				return err
+			}
+			out.WriteString(formatted)
+		default:
+			out.WriteByte(ch)
+		}
+	}
+
+	return &object.String{Value: out.String()}
+}
+
+// Finds the argument a placeholder of format refers to. An empty key takes the next
+// positional argument.
+func placeholderValue(key string, values []object.Object, next *int) (object.Object, *object.Error) {
+	if key == "" || isDigits(key) {
+		index := *next
+		if key == "" {
+			*next++
+		} else {
+			index, _ = strconv.Atoi(key)
+		}
+		if index >= len(values) {
+			return nil, newError("no argument for placeholder {%s} in format string", key)
+		}
+		return values[index], nil
+	}
+
+	if len(values) == 0 {
+		return nil, newError("named placeholder {%s} needs a HASH as the last argument", key)
+	}
+	named := values[len(values)-1]
+	switch named.(type) {
+	case *object.Hash, *object.Struct, *object.Instance:
+	default:
+		return nil, newError("named placeholder {%s} needs a HASH as the last argument, got %s", key, named.Type())
+	}
+
+	value, ok := lookupKey(named, key)
+	if !ok {
+		return nil, newError("no argument named %s in format string", key)
+	}
+	return value, nil
+}
+
+// Parses the layout after the colon of a format placeholder
+func parseFormatSpec(layout string) (formatSpec, *object.Error) {
+	spec := newFormatSpec()
+	rest := layout
+
+	// A fill character can only be given along with an alignment
+	if r, size := utf8.DecodeRuneInString(rest); size > 0 && len(rest) > size && isAlignment(rest[size]) {
+		spec.fill, spec.align = r, rest[size]
+		rest = rest[size+1:]
+	} else if len(rest) > 0 && isAlignment(rest[0]) {
+		spec.align = rest[0]
+		rest = rest[1:]
+	}
+
+	if len(rest) > 0 && (rest[0] == '+' || rest[0] == ' ') {
+		spec.sign = rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) > 0 && rest[0] == '0' {
+		spec.zero = true
+		rest = rest[1:]
+	}
+
+	var err *object.Error
+	if spec.width, rest, err = readNumber(rest, "width", maxWidth); err != nil {
+		return spec, err
+	}
+	if strings.HasPrefix(rest, ".") {
+		if spec.precision, rest, err = readNumber(rest[1:], "precision", maxPrecision); err != nil {
+			return spec, err
+		}
+		if spec.precision < 0 {
+			return spec, newError("missing precision in format spec %q", layout)
+		}
+	}
+
+	if len(rest) > 1 {
+		return spec, newError("invalid format spec %q", layout)
+	}
+	if len(rest) == 1 {
+		spec.verb = rest[0]
+	}
+	return spec, nil
+}
+
+// sprintf(template, args...) is printf-style formatting: each %[flags][width][.precision]verb
+// in template formats the next argument, and %% stands for a percent sign. The flags
+// are - to align left, + and space to mark positive numbers, and 0 to pad numbers with
+// zeros. The verbs are d x X o b f s q and v, the default representation.
+func builtinSprintf(args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newError("wrong number of arguments. got=%d, want=at least 1", len(args))
+	}
+	template, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `sprintf` must be STRING, got %s", args[0].Type())
+	}
+	values := args[1:]
+
+	var out strings.Builder
+	next := 0
+	text := template.Value
+	for i := 0; i < len(text); i++ {
+		if text[i] != '%' {
+			out.WriteByte(text[i])
+			continue
+		}
+
+		i++
+		if i < len(text) && text[i] == '%' {
+			out.WriteByte('%')
+			continue
+		}
+
+		spec := newFormatSpec()
+		spec.align = '>'
+	flags:
+		for ; i < len(text); i++ {
+			switch text[i] {
+			case '-':
+				spec.align = '<'
+			case '+', ' ':
+				spec.sign = text[i]
+			case '0':
+				spec.zero = true
+			default:
+				break flags
+			}
+		}
+
+		var rest string
+		var err *object.Error
+		if spec.width, rest, err = readNumber(text[i:], "width", maxWidth); err != nil {
+			return err
+		}
+		if strings.HasPrefix(rest, ".") {
+			if spec.precision, rest, err = readNumber(rest[1:], "precision", maxPrecision); err != nil {
+				return err
+			}
+			if spec.precision < 0 {
+				spec.precision = 0
+			}
+		}
+		if rest == "" {
+			return newError("incomplete verb at end of format string")
+		}
+		spec.verb = rest[0]
+		if spec.verb == 'v' {
+			spec.verb = 0
+		}
+		i = len(text) - len(rest)
+
+		if spec.align == '<' {
+			// Zeros would change the number when put on the right
+			spec.zero = false
+		}
+
+		if next >= len(values) {
+			return newError("no argument for %%%c in format string", rest[0])
+		}
+		formatted, err := formatValue(values[next], spec)
+		if err != nil {
+			return err
+		}
+		next++
+		out.WriteString(formatted)
+	}
+
+	if next < len(values) {
+		return newError("too many arguments for format string: got %d, used %d", len(values), next)
+	}
+	return &object.String{Value: out.String()}
+}
+
+// Formats a value for one placeholder
+func formatValue(value object.Object, spec formatSpec) (string, *object.Error) {
+	numeric := isNumeric(value)
+	verb := spec.verb
+	if verb == 0 && spec.precision >= 0 && numeric {
+		verb = 'f'
+	}
+
+	var body string
+	switch verb {
+	case 0, 's':
+		if str, ok := value.(*object.String); ok {
+			body = str.Value
+		} else {
+			body = value.Inspect()
+		}
+		if verb == 's' {
+			numeric = false
+			if spec.precision >= 0 && utf8.RuneCountInString(body) > spec.precision {
+				body = string([]rune(body)[:spec.precision])
+			}
+		}
+	case 'q':
+		numeric = false
+		if str, ok := value.(*object.String); ok {
+			body = strconv.Quote(str.Value)
+		} else {
+			body = strconv.Quote(value.Inspect())
+		}
+	case 'd', 'x', 'X', 'o', 'b':
+		integer, ok := integerValue(value)
+		if !ok {
+			return "", newError("format verb %c needs an INTEGER, got %s", verb, value.Type())
+		}
+		base := map[byte]int{'d': 10, 'x': 16, 'X': 16, 'o': 8, 'b': 2}[verb]
+		body = integer.Text(base)
+		if verb == 'X' {
+			body = strings.ToUpper(body)
+		}
+	case 'f':
+		if !numeric {
+			return "", newError("format verb f needs a number, got %s", value.Type())
+		}
+		precision := spec.precision
+		if precision < 0 {
+			precision = 6
+		}
+		body = toDecimal(value).Rescale(precision, object.RoundHalfEven).Inspect()
+	default:
+		return "", newError("unsupported format verb %c for %s", verb, value.Type())
+	}
+
+	if !numeric {
+		return pad(body, spec, '<'), nil
+	}
+
+	sign := ""
+	if strings.HasPrefix(body, "-") {
+		sign, body = "-", body[1:]
+	} else if spec.sign != 0 {
+		sign = string(spec.sign)
+	}
+
+	if spec.zero {
+		if missing := spec.width - len(sign) - len(body); missing > 0 {
+			body = strings.Repeat("0", missing) + body
+		}
+	}
+	return pad(sign+body, spec, '>'), nil
+}
+
+// Pads s to the width of spec, aligning it as spec says or else as given
+func pad(s string, spec formatSpec, align byte) string {
+	missing := spec.width - utf8.RuneCountInString(s)
+	if missing <= 0 {
+		return s
+	}
+	if spec.align != 0 {
+		align = spec.align
+	}
+
+	fill := string(spec.fill)
+	switch align {
+	case '<':
+		return s + strings.Repeat(fill, missing)
+	case '^':
+		left := missing / 2
+		return strings.Repeat(fill, left) + s + strings.Repeat(fill, missing-left)
+	default:
+		return strings.Repeat(fill, missing) + s
+	}
+}
+
+func integerValue(obj object.Object) (*big.Int, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return big.NewInt(obj.Value), true
+	case *object.BigInt:
+		return obj.Value, true
+	default:
+		return nil, false
+	}
+}
+
+func isAlignment(ch byte) bool {
+	return ch == '<' || ch == '>' || ch == '^'
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Reads the number s starts with, returning -1 if there is none, and what follows it.
+// A number above limit is an error, named by what it sets.
+func readNumber(s string, what string, limit int) (int, string, *object.Error) {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return -1, s, nil
+	}
+
+	n, err := strconv.Atoi(s[:end])
+	if err != nil || n > limit {
+		return 0, s, newError("%s in format string must be at most %d, got %s", what, limit, s[:end])
+	}
+	return n, s[end:], nil
+}
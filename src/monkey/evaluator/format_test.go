@@ -0,0 +1,72 @@
+package evaluator
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`format("{} items cost {price:.2f}", 3, {"price": 9.5d})`, "3 items cost 9.50"},
+		{`format("{1} {0} {1}", "a", "b")`, "b a b"},
+		{`format("{{}} {}", 1)`, "{} 1"},
+		{`format("[{:5}] [{:<5}] [{:^5}]", 42, 42, "ab")`, "[   42] [42   ] [ ab  ]"},
+		{`format("[{:5}]", "ab")`, "[ab   ]"},
+		{`format("{:*>6}", "ab")`, "****ab"},
+		{`format("{:x} {:X} {:o} {:b}", 255, 255, 8, 5)`, "ff FF 10 101"},
+		{`format("{:08.3f}", -3.14159d)`, "-003.142"},
+		{`format("{:+d} {:+d}", 5, -5)`, "+5 -5"},
+		{`format("{:.1}", 2)`, "2.0"},
+		{`format("{:.3s}", "abcdef")`, "abc"},
+		{`format("{} {} {}", true, [1, 2], {"a": 1})`, `true [1, 2] {a: 1}`},
+		{`format("{:q}", "hi")`, `"hi"`},
+		{`format("{:d}", true)`, errorMessage("format verb d needs an INTEGER, got BOOLEAN")},
+		{`format("{:f}", "x")`, errorMessage("format verb f needs a number, got STRING")},
+		{`format("{:z}", 1)`, errorMessage("unsupported format verb z for INTEGER")},
+		{`format("{:5dd}", 1)`, errorMessage(`invalid format spec "5dd"`)},
+		{`format("{} {}", 1)`, errorMessage("no argument for placeholder {} in format string")},
+		{`format("{name}", 1)`, errorMessage("named placeholder {name} needs a HASH as the last argument, got INTEGER")},
+		{`format("{name}", {"other": 1})`, errorMessage("no argument named name in format string")},
+		{`format("{", 1)`, errorMessage("unterminated placeholder in format string")},
+		{`format("}", 1)`, errorMessage("single } in format string")},
+		{`format(1)`, errorMessage("argument to `format` must be STRING, got INTEGER")},
+		{`len(format("{:10000}", 1))`, 10000},
+		{`format("{:10001}", 1)`, errorMessage("width in format string must be at most 10000, got 10001")},
+		{`format("{:99999999999999999999}", 1)`, errorMessage("width in format string must be at most 10000, got 99999999999999999999")},
+		{`format("{:.99999999999999999999f}", 1.5d)`, errorMessage("precision in format string must be at most 10000, got 99999999999999999999")},
+		{`format("{99999999999999999999}", 1)`, errorMessage("no argument for placeholder {99999999999999999999} in format string")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`sprintf("%d%%", 50)`, "50%"},
+		{`sprintf("[%5d] [%-5d] [%05d]", 42, 42, -42)`, "[   42] [42   ] [-0042]"},
+		{`sprintf("%x %X %o %b", 255, 255, 8, 5)`, "ff FF 10 101"},
+		{`sprintf("%.2f", 1.005d)`, "1.00"},
+		{`sprintf("%8.3f|", 2.5d)`, "   2.500|"},
+		{`sprintf("%+d % d", 3, 3)`, "+3  3"},
+		{`sprintf("%s=%v", "a", [1, true])`, "a=[1, true]"},
+		{`sprintf("%-4s|", "ab")`, "ab  |"},
+		{`sprintf("%q", "a b")`, `"a b"`},
+		{`sprintf("%x", 123456789012345678901234567890)`, "18ee90ff6c373e0ee4e3f0ad2"},
+		{`sprintf("%d", "x")`, errorMessage("format verb d needs an INTEGER, got STRING")},
+		{`sprintf("%y", 1)`, errorMessage("unsupported format verb y for INTEGER")},
+		{`sprintf("%d %d", 1)`, errorMessage("no argument for %d in format string")},
+		{`sprintf("%d", 1, 2)`, errorMessage("too many arguments for format string: got 2, used 1")},
+		{`sprintf("abc %5")`, errorMessage("incomplete verb at end of format string")},
+		{`sprintf("%99999999999999999999d", 1)`, errorMessage("width in format string must be at most 10000, got 99999999999999999999")},
+		{`sprintf("%.10001f", 1)`, errorMessage("precision in format string must be at most 10000, got 10001")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
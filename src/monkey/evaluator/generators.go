@@ -0,0 +1,176 @@
+package evaluator
+
+import (
+	"context"
+	"monkey/ast"
+	"monkey/object"
+	"runtime"
+)
+
+// A call to a generator function in progress. The function runs on its own goroutine
+// and takes turns with the code iterating it: Next hands control to the goroutine and
+// waits until the function yields or returns, and yield hands control back. Only one
+// side runs at a time, so the interpreter is never used concurrently.
+type generatorRun struct {
+	interp *Interpreter
+	body   *ast.BlockStatement
+	env    *object.Environment
+
+	resume  chan struct{}
+	results chan generatorResult
+	stop    chan struct{}
+	exited  chan struct{} // closed when the goroutine ends
+
+	started  bool
+	running  bool
+	finished bool
+	stopped  bool
+}
+
+type generatorResult struct {
+	value object.Object // the yielded element, or the error the function failed with
+	done  bool          // whether the function has returned
+}
+
+// Returns a generator that runs body in env, which holds the bound arguments. The
+// goroutine is only started by the first call to Next.
+func (interp *Interpreter) newGenerator(body *ast.BlockStatement, env *object.Environment) object.Object {
+	run := &generatorRun{
+		interp:  interp,
+		body:    body,
+		env:     env,
+		resume:  make(chan struct{}),
+		results: make(chan generatorResult),
+		stop:    make(chan struct{}),
+		exited:  make(chan struct{}),
+	}
+
+	return interp.track(&object.Generator{Resume: run.next})
+}
+
+func (run *generatorRun) next() (object.Object, bool) {
+	if run.finished {
+		return nil, false
+	}
+	if run.stopped {
+		return newError("generator was stopped"), true
+	}
+	if run.running {
+		return newError("generator is already running"), true
+	}
+
+	interp := run.interp
+	outer, depth, ctx := interp.generator, interp.depth, interp.ctx
+	interp.generator = run
+	if ctx == nil {
+		// Go code may resume a generator outside of Eval
+		interp.ctx = context.Background()
+	}
+	run.running = true
+
+	if run.started {
+		run.resume <- struct{}{}
+	} else {
+		run.started = true
+		if interp.generators == nil {
+			interp.generators = map[*generatorRun]struct{}{}
+		}
+		interp.generators[run] = struct{}{}
+		go run.run()
+	}
+	result := <-run.results
+
+	run.running = false
+	interp.generator, interp.depth, interp.ctx = outer, depth, ctx
+
+	if result.done {
+		run.finished = true
+		delete(interp.generators, run)
+		if result.value == nil {
+			return nil, false
+		}
+	}
+	return result.value, true
+}
+
+// The body of the generator's goroutine
+func (run *generatorRun) run() {
+	defer close(run.exited)
+
+	evaluated := unwrapReturnValue(run.interp.eval(run.body, run.env))
+	if isError(evaluated) {
+		run.results <- generatorResult{value: evaluated, done: true}
+		return
+	}
+	run.results <- generatorResult{done: true}
+}
+
+// Hands value to the code iterating the generator and waits to be resumed. Called on
+// the generator's goroutine.
+func (run *generatorRun) yield(value object.Object) {
+	depth := run.interp.depth
+	run.results <- generatorResult{value: value}
+
+	select {
+	case <-run.resume:
+		run.interp.depth = depth
+	case <-run.stop:
+		// The generator was stopped. The goroutine ends here without unwinding the
+		// function, so like a timeout this skips its finally blocks.
+		runtime.Goexit()
+	}
+}
+
+// Ends the goroutines of the generators waiting in yield. A generator abandoned
+// halfway would otherwise keep its goroutine, and through it the whole interpreter,
+// alive for as long as the process runs. Stopped generators cannot be resumed, and
+// their finally blocks do not run.
+func (interp *Interpreter) stopGenerators() {
+	for run := range interp.generators {
+		if run.running {
+			continue
+		}
+
+		close(run.stop)
+		<-run.exited
+		run.stopped = true
+		delete(interp.generators, run)
+	}
+}
+
+func (interp *Interpreter) evalYieldExpression(node *ast.YieldExpression, env *object.Environment) object.Object {
+	var value object.Object = NULL
+	if node.Value != nil {
+		value = interp.eval(node.Value, env)
+		if isError(value) {
+			return value
+		}
+	}
+
+	interp.generator.yield(value)
+	return NULL
+}
+
+// next(generator, default) resumes a generator and returns the element it yields, or
+// default (null if not given) once it is exhausted
+func builtinNext(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newError("wrong number of arguments. got=%d, want=1 to 2", len(args))
+	}
+	gen, ok := args[0].(*object.Generator)
+	if !ok {
+		return newError("argument to `next` must be GENERATOR, got %s", args[0].Type())
+	}
+
+	if element, ok := gen.Next(); ok {
+		return element
+	}
+	if len(args) == 2 {
+		return args[1]
+	}
+	return NULL
+}
+
+func generatorNext(receiver object.Object, args ...object.Object) object.Object {
+	return builtinNext(append([]object.Object{receiver}, args...)...)
+}
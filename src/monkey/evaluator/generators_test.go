@@ -0,0 +1,147 @@
+package evaluator
+
+import (
+	"context"
+	"monkey/object"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+const countdown = `
+let countdown = fn*(n) {
+	while (n > 0) {
+		yield n;
+		let n = n - 1;
+	}
+};
+`
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{countdown + `let g = countdown(2); next(g) * 10 + next(g)`, 21},
+		{countdown + `let g = countdown(2); next(g); next(g); next(g)`, nil},
+		{countdown + `let g = countdown(1); next(g); next(g, "done")`, "done"},
+		{countdown + `let g = countdown(3); g.next() + g.next()`, 5},
+		{countdown + `let below = fn(limit) { for (n in countdown(10)) { if (n < limit) { return n } } }; below(3)`, 2},
+		{countdown + `[...countdown(3)].join(",")`, "3,2,1"},
+		{countdown + `countdown(5).map(fn(n) { n * n }).take(2).toArray().join(",")`, "25,16"},
+		{countdown + `countdown(3).zip(1..).toArray().len()`, 3},
+		{`let naturals = fn*() { let i = 0; while (true) { yield i; let i = i + 1; } }; naturals().filter(fn(n) { n / 2 * 2 == n }).take(3).toArray().join(",")`, "0,2,4"},
+		{`let g = fn*() { yield; }(); next(g)`, nil},
+		{`let g = fn*() { yield; }(); next(g); next(g, 1)`, 1},
+		{`let g = fn*() { yield 1; return 2; yield 3; }(); [...g].join(",")`, "1"},
+		{`let g = fn*(xs) { for (x in xs) { for (y in xs) { yield x * y } } }; g([1, 2]).toArray().join(",")`, "1,2,2,4"},
+		{`let inner = fn*() { yield 1; yield 2 }; let outer = fn*() { for (x in inner()) { yield x * 10 } }; outer().toArray().join(",")`, "10,20"},
+		{countdown + `let g = countdown(3); next(g); [...g].join(",")`, "2,1"},
+		{`let g = fn*() { yield 1; 1 + true; }(); next(g); next(g)`, errorMessage("type mismatch: INTEGER + BOOLEAN")},
+		{`let g = fn*() { yield next(g) }(); next(g)`, errorMessage("generator is already running")},
+		{countdown + `countdown()`, errorMessage("wrong number of arguments. got=0, want=1")},
+		{`next([1])`, errorMessage("argument to `next` must be GENERATOR, got ARRAY")},
+		{`class Bag { fn init(self, xs) { self.xs = xs } fn* items(self) { for (x in self.xs) { yield x } } } Bag([1, 2]).items().toArray().len()`, 2},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestGeneratorInspect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`fn*(n) { yield n }`, "fn*(n) {\nyield n\n}"},
+		{`fn*() { yield 1 }()`, "generator"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong Inspect. expected=%q, got=%q", tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestAbandonedGeneratorsDoNotLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	interp := New()
+	evalWith(interp, countdown+`
+		let it = countdown(10);
+		next(it);
+		for (i in 0..1000) { let g = countdown(10); next(g) };
+	`)
+	waitForGoroutines(t, before)
+
+	testExpectedObject(t, evalWith(interp, `next(it)`), errorMessage("generator was stopped"))
+	testExpectedObject(t, evalWith(interp, `let g = countdown(2); [next(g), next(g), next(g, 0)].join(",")`), "2,1,0")
+}
+
+func TestStoppedGeneratorsSkipFinally(t *testing.T) {
+	var out strings.Builder
+	interp := New(WithStdout(&out))
+	evalWith(interp, `
+		let g = fn*() { try { yield 1 } finally { puts("finally") } };
+		let it = g();
+		next(it);
+	`)
+	if out.String() != "" {
+		t.Errorf("finally block of a stopped generator ran. got=%q", out.String())
+	}
+}
+
+func TestCloseStopsGeneratorsResumedFromGo(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	interp := New()
+	gen, ok := evalWith(interp, countdown+`countdown(3)`).(*object.Generator)
+	if !ok {
+		t.Fatal("expected a generator")
+	}
+	testExpectedObject(t, nextOf(gen), 3)
+	testExpectedObject(t, nextOf(gen), 2)
+
+	interp.Close()
+	waitForGoroutines(t, before)
+	testExpectedObject(t, nextOf(gen), errorMessage("generator was stopped"))
+}
+
+func nextOf(gen *object.Generator) object.Object {
+	element, _ := gen.Next()
+	return element
+}
+
+func TestCancelledEvaluationStopsGenerators(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	interp := New()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	evaluated := evalWithContext(ctx, interp, countdown+`
+		let it = countdown(10);
+		next(it);
+		while (true) {}
+	`)
+	testExpectedObject(t, evaluated, errorMessage("evaluation timed out"))
+
+	waitForGoroutines(t, before)
+	testExpectedObject(t, evalWith(interp, `next(it)`), errorMessage("generator was stopped"))
+}
+
+// Waits for the number of goroutines to drop back to want, failing if it does not
+func waitForGoroutines(t *testing.T, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Fatalf("generator goroutines leaked. before=%d, after=%d", want, runtime.NumGoroutine())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,170 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"monkey/ast"
+	"monkey/object"
+	"os"
+)
+
+// An Interpreter evaluates Monkey programs. Every interpreter owns its global
+// environment, builtins and configuration, so several of them can run side by side
+// in the same process. A single Interpreter must not be used from several
+// goroutines at once.
+type Interpreter struct {
+	globals  *object.Environment
+	builtins map[string]*object.Builtin
+	methods  map[object.ObjectType]map[string]Method
+
+	stdout       io.Writer
+	stderr       io.Writer
+	hooks        Hooks
+	limits       Limits
+	capabilities Capabilities
+
+	checkedArithmetic    bool
+	decimalDivisionScale int
+
+	ctx        context.Context
+	depth      int
+	usage      Usage
+	generator  *generatorRun              // the generator whose function is running, if any
+	generators map[*generatorRun]struct{} // generators that have started but not finished
+}
+
+// Hooks let embedders observe evaluation. Any of them may be left nil.
+type Hooks struct {
+	// Called before a function or builtin is applied to its arguments
+	OnCall func(fn object.Object, args []object.Object)
+	// Called when a call to Eval results in an error
+	OnError func(err *object.Error)
+}
+
+// Limits restrict the resources a program may use. A zero value means unlimited.
+// Apart from MaxCallDepth they are budgets for the interpreter's whole lifetime, so
+// they are shared by every call to Eval.
+type Limits struct {
+	MaxCallDepth int
+	// Maximum number of AST nodes evaluated
+	MaxSteps int64
+	// Maximum number of objects and environments allocated
+	MaxAllocations int64
+	// Maximum estimated number of bytes allocated
+	MaxBytes int64
+}
+
+type Option func(*Interpreter)
+
+func New(options ...Option) *Interpreter {
+	interp := &Interpreter{
+		globals:              object.NewEnvironment(),
+		stdout:               os.Stdout,
+		stderr:               os.Stderr,
+		decimalDivisionScale: 16,
+	}
+	interp.builtins = interp.defaultBuiltins()
+	interp.methods = interp.defaultMethods()
+	interp.globals.Set(resultEnum.Name, resultEnum)
+
+	for _, option := range options {
+		option(interp)
+	}
+
+	return interp
+}
+
+// Sets where puts writes to. Defaults to os.Stdout.
+func WithStdout(w io.Writer) Option {
+	return func(interp *Interpreter) { interp.stdout = w }
+}
+
+// Sets where warn writes to. Defaults to os.Stderr.
+func WithStderr(w io.Writer) Option {
+	return func(interp *Interpreter) { interp.stderr = w }
+}
+
+func WithHooks(hooks Hooks) Option {
+	return func(interp *Interpreter) { interp.hooks = hooks }
+}
+
+func WithLimits(limits Limits) Option {
+	return func(interp *Interpreter) { interp.limits = limits }
+}
+
+// Integer operations that would overflow an int64 are promoted to a BigInt. With
+// checked arithmetic they produce an error instead.
+func WithCheckedArithmetic(checked bool) Option {
+	return func(interp *Interpreter) { interp.checkedArithmetic = checked }
+}
+
+// Sets the minimum number of digits after the decimal point kept when dividing
+// decimals. Quotients are rounded half-even at this scale. Defaults to 16.
+func WithDecimalDivisionScale(scale int) Option {
+	return func(interp *Interpreter) { interp.decimalDivisionScale = scale }
+}
+
+// Makes an additional builtin available to programs, replacing any builtin of the
+// same name.
+func WithBuiltin(name string, builtin *object.Builtin) Option {
+	return func(interp *Interpreter) { interp.builtins[name] = builtin }
+}
+
+// Evaluates node in the interpreter's global environment. Bindings made by one
+// call are visible to the next.
+//
+// Evaluation stops with an error once ctx is cancelled or its deadline passes. The
+// error's Cause is ctx.Err(), so timeouts can be detected with
+// errors.Is(err.Cause, context.DeadlineExceeded).
+//
+// Generators left waiting in yield are stopped when Eval returns, so they cannot be
+// resumed by a later call.
+func (interp *Interpreter) Eval(ctx context.Context, node ast.Node) object.Object {
+	defer interp.withContext(ctx)()
+
+	result := interp.eval(node, interp.globals)
+	if err, ok := result.(*object.Error); ok && interp.hooks.OnError != nil {
+		interp.hooks.OnError(err)
+	}
+	return result
+}
+
+// Stops the generators that Go code resumed outside of Eval and left waiting in
+// yield, ending their goroutines. Call it once the interpreter is no longer needed,
+// as those goroutines otherwise keep it alive. The interpreter can still be used
+// afterwards, but resuming a stopped generator is an error. Close must not be called
+// while the interpreter is evaluating.
+func (interp *Interpreter) Close() {
+	interp.stopGenerators()
+}
+
+// Returns an error once the context of the running evaluation is done. It is checked
+// on every loop iteration and function call, so runaway programs can be stopped.
+func (interp *Interpreter) checkContext() *object.Error {
+	err := interp.ctx.Err()
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return &object.Error{Message: "evaluation timed out", Cause: err}
+	default:
+		return &object.Error{Message: "evaluation cancelled", Cause: err}
+	}
+}
+
+// Makes ctx the context of the running evaluation until the returned function is
+// called. Go functions called by a program may call back into the interpreter, so
+// the previous context is restored afterwards. Once the outermost evaluation ends,
+// the generators it left waiting in yield are stopped, so that abandoned ones do not
+// pile up.
+func (interp *Interpreter) withContext(ctx context.Context) func() {
+	previous := interp.ctx
+	interp.ctx = ctx
+	return func() {
+		if previous == nil {
+			interp.stopGenerators()
+		}
+		interp.ctx = previous
+	}
+}
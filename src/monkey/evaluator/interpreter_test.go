@@ -0,0 +1,192 @@
+package evaluator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"monkey/lexer"
+	"monkey/object"
+	"monkey/parser"
+	"testing"
+	"time"
+)
+
+func TestInterpretersAreIsolated(t *testing.T) {
+	first := New()
+	second := New()
+
+	testIntegerObject(t, evalWith(first, "let x = 1; x"), 1)
+	testIntegerObject(t, evalWith(second, "let x = 2; x"), 2)
+	testIntegerObject(t, evalWith(first, "x"), 1)
+
+	errObj, ok := evalWith(New(), "x").(*object.Error)
+	if !ok || errObj.Message != "identifier not found: x" {
+		t.Errorf("expected fresh interpreter to have no globals. got=%+v", errObj)
+	}
+}
+
+func TestOutputWriters(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	interp := New(WithStdout(&stdout), WithStderr(&stderr))
+
+	testNullObject(t, evalWith(interp, `puts("hello", 1 + 2); warn("careful")`))
+
+	if stdout.String() != "hello\n3\n" {
+		t.Errorf("stdout has wrong content. got=%q", stdout.String())
+	}
+	if stderr.String() != "careful\n" {
+		t.Errorf("stderr has wrong content. got=%q", stderr.String())
+	}
+}
+
+func TestHooks(t *testing.T) {
+	calls := 0
+	var lastError *object.Error
+	interp := New(WithHooks(Hooks{
+		OnCall:  func(fn object.Object, args []object.Object) { calls++ },
+		OnError: func(err *object.Error) { lastError = err },
+	}))
+
+	evalWith(interp, "let f = fn(x) { x }; f(f(1))")
+	if calls != 2 {
+		t.Errorf("OnCall called wrong number of times. got=%d, want=2", calls)
+	}
+
+	evalWith(interp, "f(true + 1)")
+	if lastError == nil || lastError.Message != "type mismatch: BOOLEAN + INTEGER" {
+		t.Errorf("OnError not called with the error. got=%+v", lastError)
+	}
+}
+
+func TestWithBuiltin(t *testing.T) {
+	interp := New(WithBuiltin("answer", &object.Builtin{
+		Fn: func(args ...object.Object) object.Object { return &object.Integer{Value: 42} },
+	}))
+
+	testIntegerObject(t, evalWith(interp, "answer()"), 42)
+}
+
+func TestMaxCallDepth(t *testing.T) {
+	interp := New(WithLimits(Limits{MaxCallDepth: 10}))
+
+	evaluated := evalWith(interp, "let loop = fn(n) { loop(n + 1) }; loop(0)")
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "maximum call depth of 10 exceeded" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+
+	testIntegerObject(t, evalWith(interp, "let count = fn(n) { if (n > 0) { count(n - 1) } else { 0 } }; count(5)"), 0)
+}
+
+func TestTimeout(t *testing.T) {
+	tests := []string{
+		"while (true) {}",
+		"let loop = fn() { loop() }; loop()",
+	}
+
+	for _, input := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		evaluated := evalWithContext(ctx, New(), input)
+		cancel()
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+		}
+		if errObj.Message != "evaluation timed out" {
+			t.Errorf("wrong error message. got=%q", errObj.Message)
+		}
+		if !errors.Is(errObj.Cause, context.DeadlineExceeded) {
+			t.Errorf("error is not caused by the deadline. got=%v", errObj.Cause)
+		}
+	}
+}
+
+func TestCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	evaluated := evalWithContext(ctx, New(), "let f = fn() { 1 }; f()")
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "evaluation cancelled" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+	if !errors.Is(errObj.Cause, context.Canceled) {
+		t.Errorf("error is not caused by the cancellation. got=%v", errObj.Cause)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		input           string
+		limits          Limits
+		expectedMessage string
+	}{
+		{"while (true) {}", Limits{MaxSteps: 1000}, "step limit of 1000 exceeded"},
+		{"let i = 0; while (true) { let i = i + 1; }", Limits{MaxAllocations: 100}, "allocation limit of 100 exceeded"},
+		{`let s = "x"; while (true) { let s = s + s; }`, Limits{MaxBytes: 1 << 16}, "memory limit of 65536 bytes exceeded"},
+		{"let f = fn() { f() }; f()", Limits{MaxAllocations: 100}, "allocation limit of 100 exceeded"},
+	}
+
+	for _, tt := range tests {
+		evaluated := evalWith(New(WithLimits(tt.limits)), tt.input)
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q", tt.expectedMessage, errObj.Message)
+		}
+		if !errors.Is(errObj.Cause, ErrLimitExceeded) {
+			t.Errorf("error is not caused by a limit. got=%v", errObj.Cause)
+		}
+	}
+}
+
+func TestUsageIsDeterministic(t *testing.T) {
+	input := `
+let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } };
+let s = "";
+let i = 0;
+while (i < 10) { let s = s + "ab"; let i = i + 1; }
+fib(10)`
+
+	first := New()
+	second := New()
+	testIntegerObject(t, evalWith(first, input), 55)
+	testIntegerObject(t, evalWith(second, input), 55)
+
+	if first.Usage() != second.Usage() {
+		t.Errorf("usage differs between runs. first=%+v, second=%+v", first.Usage(), second.Usage())
+	}
+	if first.Usage().Steps == 0 || first.Usage().Allocations == 0 || first.Usage().Bytes == 0 {
+		t.Errorf("usage was not recorded. got=%+v", first.Usage())
+	}
+
+	// The program, its statement, the infix expression and both literals are steps
+	interp := New()
+	evalWith(interp, "1 + 2")
+	if interp.Usage() != (Usage{Steps: 5, Allocations: 3, Bytes: 48}) {
+		t.Errorf("wrong usage for 1 + 2. got=%+v", interp.Usage())
+	}
+}
+
+func evalWith(interp *Interpreter, input string) object.Object {
+	return evalWithContext(context.Background(), interp, input)
+}
+
+func evalWithContext(ctx context.Context, interp *Interpreter, input string) object.Object {
+	lex := lexer.New(input)
+	par := parser.New(lex)
+	program := par.ParseProgram()
+
+	return interp.Eval(ctx, program)
+}
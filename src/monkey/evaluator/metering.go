@@ -0,0 +1,107 @@
+package evaluator
+
+import (
+	"errors"
+	"fmt"
+	"monkey/object"
+)
+
+// The Cause of errors returned when a program exceeds one of its Limits
+var ErrLimitExceeded = errors.New("resource limit exceeded")
+
+// Usage is the resources consumed by the programs an interpreter has evaluated. It
+// only depends on the programs themselves, so the same programs always produce
+// the same usage.
+type Usage struct {
+	Steps       int64
+	Allocations int64
+	// An estimate of the bytes allocated for objects. Memory that is no longer
+	// reachable is not subtracted again.
+	Bytes int64
+}
+
+// Estimated sizes of the objects the interpreter allocates, in bytes
+const (
+	objectSize      = 16
+	environmentSize = 48
+	functionSize    = 48
+	elementSize     = 16
+	hashPairSize    = 48
+)
+
+func (interp *Interpreter) Usage() Usage {
+	return interp.usage
+}
+
+// Counts a single evaluation step
+func (interp *Interpreter) step() *object.Error {
+	interp.usage.Steps++
+	if interp.limits.MaxSteps > 0 && interp.usage.Steps > interp.limits.MaxSteps {
+		return newLimitError("step limit of %d exceeded", interp.limits.MaxSteps)
+	}
+	return nil
+}
+
+// Counts an allocation of the given size
+func (interp *Interpreter) allocate(size int64) *object.Error {
+	interp.usage.Allocations++
+	interp.usage.Bytes += size
+
+	if interp.limits.MaxAllocations > 0 && interp.usage.Allocations > interp.limits.MaxAllocations {
+		return newLimitError("allocation limit of %d exceeded", interp.limits.MaxAllocations)
+	}
+	if interp.limits.MaxBytes > 0 && interp.usage.Bytes > interp.limits.MaxBytes {
+		return newLimitError("memory limit of %d bytes exceeded", interp.limits.MaxBytes)
+	}
+	return nil
+}
+
+// Counts obj as a new allocation and returns it, or an error if that exceeds a
+// limit. The shared booleans and null, as well as errors, are passed through.
+func (interp *Interpreter) track(obj object.Object) object.Object {
+	switch obj {
+	case nil, NULL, TRUE, FALSE:
+		return obj
+	}
+	if isError(obj) {
+		return obj
+	}
+
+	if err := interp.allocate(sizeOf(obj)); err != nil {
+		return err
+	}
+	return obj
+}
+
+func sizeOf(obj object.Object) int64 {
+	switch obj := obj.(type) {
+	case *object.String:
+		return objectSize + int64(len(obj.Value))
+	case *object.BigInt:
+		return objectSize + int64(len(obj.Value.Bits()))*8
+	case *object.Decimal:
+		return objectSize + int64(len(obj.Value.Bits()))*8
+	case *object.Function:
+		return functionSize
+	case *object.Array:
+		return objectSize + int64(len(obj.Elements))*elementSize
+	case *object.Hash:
+		return objectSize + int64(len(obj.Pairs))*hashPairSize
+	case *object.Struct:
+		return objectSize + int64(len(obj.Values))*elementSize
+	case *object.Instance:
+		return objectSize + int64(len(obj.Fields))*hashPairSize
+	case *object.Class:
+		return objectSize + int64(len(obj.Methods))*functionSize
+	case *object.Enum:
+		return objectSize + int64(len(obj.Variants))*elementSize
+	case *object.EnumValue:
+		return objectSize + int64(len(obj.Values))*elementSize
+	default:
+		return objectSize
+	}
+}
+
+func newLimitError(format string, a ...interface{}) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...), Cause: ErrLimitExceeded}
+}
@@ -0,0 +1,482 @@
+package evaluator
+
+import (
+	"fmt"
+	"math/big"
+	"monkey/object"
+	"sort"
+	"strings"
+)
+
+// A method of a built-in type, called as receiver.name(args...)
+type Method func(receiver object.Object, args ...object.Object) object.Object
+
+func (interp *Interpreter) defaultMethods() map[object.ObjectType]map[string]Method {
+	return map[object.ObjectType]map[string]Method{
+		object.STRING_OBJ: {
+			"len":        methodLen,
+			"upper":      stringUpper,
+			"lower":      stringLower,
+			"split":      stringSplit,
+			"trim":       stringTrim,
+			"replace":    stringReplace,
+			"contains":   stringContains,
+			"startsWith": stringStartsWith,
+			"endsWith":   stringEndsWith,
+		},
+		object.ARRAY_OBJ: {
+			"len":    methodLen,
+			"map":    interp.arrayMap,
+			"filter": interp.arrayFilter,
+			"reduce": interp.arrayReduce,
+			"sort":   interp.arraySort,
+			"join":   arrayJoin,
+			"slice":  arraySlice,
+		},
+		object.HASH_OBJ: {
+			"len":    methodLen,
+			"keys":   hashKeys,
+			"values": hashValues,
+			"has":    hashHas,
+			"delete": hashDelete,
+			"merge":  hashMerge,
+		},
+		object.RANGE_OBJ: {
+			"len":     rangeLen,
+			"map":     interp.sequenceMap,
+			"filter":  interp.sequenceFilter,
+			"take":    interp.sequenceTake,
+			"zip":     interp.sequenceZip,
+			"toArray": interp.sequenceToArray,
+		},
+		object.SEQUENCE_OBJ: {
+			"map":     interp.sequenceMap,
+			"filter":  interp.sequenceFilter,
+			"take":    interp.sequenceTake,
+			"zip":     interp.sequenceZip,
+			"toArray": interp.sequenceToArray,
+		},
+		object.GENERATOR_OBJ: {
+			"next":    generatorNext,
+			"map":     interp.sequenceMap,
+			"filter":  interp.sequenceFilter,
+			"take":    interp.sequenceTake,
+			"zip":     interp.sequenceZip,
+			"toArray": interp.sequenceToArray,
+		},
+		object.INTEGER_OBJ: {
+			"abs": integerAbs,
+			"str": methodStr,
+		},
+	}
+}
+
+// The Option form of DefineMethod
+func WithMethod(typ object.ObjectType, name string, method Method) Option {
+	return func(interp *Interpreter) { interp.DefineMethod(typ, name, method) }
+}
+
+// Adds a method to the built-in type typ, replacing any method of the same name.
+// Methods of INTEGER are also called on integers too large for an int64, whose
+// receiver is then an *object.BigInt. A panic in the method becomes an error.
+func (interp *Interpreter) DefineMethod(typ object.ObjectType, name string, method Method) {
+	typ = methodTable(typ)
+	if interp.methods[typ] == nil {
+		interp.methods[typ] = map[string]Method{}
+	}
+	interp.methods[typ][name] = recoverMethod(string(typ)+"."+name, method)
+}
+
+// Wraps a method defined by the embedder so that a panic in it cannot bring down
+// the program embedding the interpreter
+func recoverMethod(name string, method Method) Method {
+	return func(receiver object.Object, args ...object.Object) (result object.Object) {
+		defer func() {
+			if r := recover(); r != nil {
+				err, ok := r.(error)
+				if !ok {
+					err = fmt.Errorf("%v", r)
+				}
+				result = &object.Error{Message: fmt.Sprintf("panic in `%s`: %v", name, r), Cause: err}
+			}
+		}()
+		return method(receiver, args...)
+	}
+}
+
+// Evaluates obj.name. Hash keys take precedence over hash methods, and for host
+// objects the Go field or method is looked up. Everything else resolves to a method
+// bound to obj.
+func (interp *Interpreter) evalMemberExpression(obj object.Object, name string) object.Object {
+	switch obj := obj.(type) {
+	case *object.Hash:
+		if value, ok := obj.Get(&object.String{Value: name}); ok {
+			return value
+		}
+	case *object.HostObject:
+		return evalHostMember(obj, name)
+	case *object.Struct:
+		if value, ok := obj.Get(name); ok {
+			return value
+		}
+	case *object.Instance:
+		return interp.evalInstanceMember(obj, name)
+	case *object.Class:
+		return evalClassMember(obj, name)
+	case *object.Enum:
+		return evalEnumMember(obj, name)
+	case *object.EnumValue:
+		return evalEnumValueMember(obj, name)
+	case *object.Exception:
+		return evalExceptionMember(obj, name)
+	}
+
+	method, ok := interp.methods[methodTable(obj.Type())][name]
+	if !ok {
+		switch obj := obj.(type) {
+		case *object.Hash:
+			return NULL
+		case *object.Struct:
+			return newError("%s has no field %s", obj.Definition.Name, name)
+		}
+		return newError("unknown method: %s.%s", obj.Type(), name)
+	}
+
+	return interp.track(&object.Builtin{Fn: func(args ...object.Object) object.Object {
+		return method(obj, args...)
+	}})
+}
+
+// Returns the type whose method table values of typ use. Integers are promoted to
+// BigInts and demoted back as their values change, so both share the INTEGER table.
+func methodTable(typ object.ObjectType) object.ObjectType {
+	if typ == object.BIGINT_OBJ {
+		return object.INTEGER_OBJ
+	}
+	return typ
+}
+
+func methodLen(receiver object.Object, args ...object.Object) object.Object {
+	return builtinLen(append([]object.Object{receiver}, args...)...)
+}
+
+func methodStr(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+	return &object.String{Value: receiver.Inspect()}
+}
+
+// ===================
+// String Methods
+// ===================
+func stringUpper(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+	return &object.String{Value: strings.ToUpper(receiver.(*object.String).Value)}
+}
+
+func stringLower(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+	return &object.String{Value: strings.ToLower(receiver.(*object.String).Value)}
+}
+
+func stringTrim(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+	return &object.String{Value: strings.TrimSpace(receiver.(*object.String).Value)}
+}
+
+func stringSplit(receiver object.Object, args ...object.Object) object.Object {
+	strArgs, err := stringArguments("split", 1, args)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(receiver.(*object.String).Value, strArgs[0])
+	elements := make([]object.Object, len(parts))
+	for i, part := range parts {
+		elements[i] = &object.String{Value: part}
+	}
+	return &object.Array{Elements: elements}
+}
+
+func stringReplace(receiver object.Object, args ...object.Object) object.Object {
+	strArgs, err := stringArguments("replace", 2, args)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ReplaceAll(receiver.(*object.String).Value, strArgs[0], strArgs[1])}
+}
+
+func stringContains(receiver object.Object, args ...object.Object) object.Object {
+	strArgs, err := stringArguments("contains", 1, args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.Contains(receiver.(*object.String).Value, strArgs[0]))
+}
+
+func stringStartsWith(receiver object.Object, args ...object.Object) object.Object {
+	strArgs, err := stringArguments("startsWith", 1, args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.HasPrefix(receiver.(*object.String).Value, strArgs[0]))
+}
+
+func stringEndsWith(receiver object.Object, args ...object.Object) object.Object {
+	strArgs, err := stringArguments("endsWith", 1, args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.HasSuffix(receiver.(*object.String).Value, strArgs[0]))
+}
+
+// Checks that exactly want arguments were passed and that all of them are strings
+func stringArguments(name string, want int, args []object.Object) ([]string, *object.Error) {
+	if len(args) != want {
+		return nil, newError("wrong number of arguments. got=%d, want=%d", len(args), want)
+	}
+
+	values := make([]string, len(args))
+	for i, arg := range args {
+		str, ok := arg.(*object.String)
+		if !ok {
+			return nil, newError("argument to `%s` must be STRING, got %s", name, arg.Type())
+		}
+		values[i] = str.Value
+	}
+	return values, nil
+}
+
+// ===================
+// Array Methods
+// ===================
+func (interp *Interpreter) arrayMap(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+
+	elements := receiver.(*object.Array).Elements
+	mapped := make([]object.Object, len(elements))
+	for i, element := range elements {
+		result := interp.applyFunction(args[0], []object.Object{element})
+		if isError(result) {
+			return result
+		}
+		mapped[i] = result
+	}
+	return &object.Array{Elements: mapped}
+}
+
+func (interp *Interpreter) arrayFilter(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+
+	filtered := []object.Object{}
+	for _, element := range receiver.(*object.Array).Elements {
+		result := interp.applyFunction(args[0], []object.Object{element})
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) {
+			filtered = append(filtered, element)
+		}
+	}
+	return &object.Array{Elements: filtered}
+}
+
+// reduce(fn, initial) folds the array from the left, calling fn(accumulator, element)
+func (interp *Interpreter) arrayReduce(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+
+	accumulator := args[1]
+	for _, element := range receiver.(*object.Array).Elements {
+		accumulator = interp.applyFunction(args[0], []object.Object{accumulator, element})
+		if isError(accumulator) {
+			return accumulator
+		}
+	}
+	return accumulator
+}
+
+// sort() orders the elements with <, sort(fn) with fn(a, b) returning whether a
+// comes before b. The sort is stable and returns a new array.
+func (interp *Interpreter) arraySort(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return newError("wrong number of arguments. got=%d, want=0 or 1", len(args))
+	}
+
+	elements := receiver.(*object.Array).Elements
+	sorted := make([]object.Object, len(elements))
+	copy(sorted, elements)
+
+	var sortErr object.Object
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sortErr != nil {
+			return false
+		}
+
+		var less object.Object
+		if len(args) == 1 {
+			less = interp.applyFunction(args[0], []object.Object{sorted[i], sorted[j]})
+		} else {
+			less = interp.evalInfixExpression("<", sorted[i], sorted[j])
+		}
+
+		if isError(less) {
+			sortErr = less
+			return false
+		}
+		return isTruthy(less)
+	})
+
+	if sortErr != nil {
+		return sortErr
+	}
+	return &object.Array{Elements: sorted}
+}
+
+func arrayJoin(receiver object.Object, args ...object.Object) object.Object {
+	separator, err := stringArguments("join", 1, args)
+	if err != nil {
+		return err
+	}
+
+	parts := []string{}
+	for _, element := range receiver.(*object.Array).Elements {
+		parts = append(parts, element.Inspect())
+	}
+	return &object.String{Value: strings.Join(parts, separator[0])}
+}
+
+// slice(start, end) returns the elements from start up to, but not including, end.
+// end defaults to the length of the array and both are clamped to its bounds.
+func arraySlice(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2", len(args))
+	}
+
+	elements := receiver.(*object.Array).Elements
+	bounds := []int64{0, int64(len(elements))}
+	for i, arg := range args {
+		integer, ok := arg.(*object.Integer)
+		if !ok {
+			return newError("argument to `slice` must be INTEGER, got %s", arg.Type())
+		}
+		bounds[i] = min(max(integer.Value, 0), int64(len(elements)))
+	}
+
+	start, end := bounds[0], max(bounds[0], bounds[1])
+	sliced := make([]object.Object, end-start)
+	copy(sliced, elements[start:end])
+	return &object.Array{Elements: sliced}
+}
+
+// ===================
+// Hash Methods
+// ===================
+func hashKeys(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+
+	keys := []object.Object{}
+	for _, pair := range receiver.(*object.Hash).Entries() {
+		keys = append(keys, pair.Key)
+	}
+	return &object.Array{Elements: keys}
+}
+
+func hashValues(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+
+	values := []object.Object{}
+	for _, pair := range receiver.(*object.Hash).Entries() {
+		values = append(values, pair.Value)
+	}
+	return &object.Array{Elements: values}
+}
+
+func hashHas(receiver object.Object, args ...object.Object) object.Object {
+	key, err := hashKeyArgument(args)
+	if err != nil {
+		return err
+	}
+
+	_, ok := receiver.(*object.Hash).Get(key)
+	return nativeBoolToBooleanObject(ok)
+}
+
+// delete(key) returns a copy of the hash without key
+func hashDelete(receiver object.Object, args ...object.Object) object.Object {
+	key, err := hashKeyArgument(args)
+	if err != nil {
+		return err
+	}
+
+	hash := copyHash(receiver.(*object.Hash))
+	hash.Delete(key)
+	return hash
+}
+
+// merge(other) returns a new hash with the pairs of both hashes. Keys in other
+// replace those of the receiver.
+func hashMerge(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	other, ok := args[0].(*object.Hash)
+	if !ok {
+		return newError("argument to `merge` must be HASH, got %s", args[0].Type())
+	}
+
+	hash := copyHash(receiver.(*object.Hash))
+	for _, pair := range other.Entries() {
+		hash.Set(pair.Key.(object.Hashable), pair.Value)
+	}
+	return hash
+}
+
+func hashKeyArgument(args []object.Object) (object.Hashable, *object.Error) {
+	if len(args) != 1 {
+		return nil, newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	key, ok := args[0].(object.Hashable)
+	if !ok {
+		return nil, newError("unusable as hash key: %s", args[0].Type())
+	}
+	return key, nil
+}
+
+func copyHash(hash *object.Hash) *object.Hash {
+	copied := object.NewHash()
+	for _, pair := range hash.Entries() {
+		copied.Set(pair.Key.(object.Hashable), pair.Value)
+	}
+	return copied
+}
+
+// ===================
+// Integer Methods
+// ===================
+func integerAbs(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+
+	if integer, ok := receiver.(*object.Integer); ok && integer.Value >= 0 {
+		return receiver
+	}
+	return normalizeBigInt(new(big.Int).Abs(toBigInt(receiver)))
+}
@@ -0,0 +1,114 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"strings"
+	"testing"
+)
+
+func TestStringMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"a,b,c".split(",").len()`, 3},
+		{`"a,b,c".split(",")[1]`, "b"},
+		{`"  padded   ".trim()`, "padded"},
+		{`"one fish two fish".replace("fish", "cat")`, "one cat two cat"},
+		{`"monkey".contains("key")`, true},
+		{`"monkey".contains("ape")`, false},
+		{`"monkey".startsWith("mon")`, true},
+		{`"monkey".endsWith("mon")`, false},
+		{`"a".split(1)`, errorMessage("argument to `split` must be STRING, got INTEGER")},
+		{`"a".replace("a")`, errorMessage("wrong number of arguments. got=1, want=2")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestArrayMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, 2, 3, 4].filter(fn(x) { x > 2 }).join(",")`, "3,4"},
+		{`[1, 2, 3, 4].reduce(fn(acc, x) { acc + x }, 0)`, 10},
+		{`[].reduce(fn(acc, x) { acc + x }, 7)`, 7},
+		{`[3, 1, 2].sort().join(" ")`, "1 2 3"},
+		{`["pear", "apple", "fig"].sort().join(" ")`, "apple fig pear"},
+		{`[3, 1, 2].sort(fn(a, b) { a > b }).join(" ")`, "3 2 1"},
+		{`let a = [3, 1, 2]; a.sort(); a.join(" ")`, "3 1 2"},
+		{`[1, 2, 3, 4].slice(1, 3).join(",")`, "2,3"},
+		{`[1, 2, 3, 4].slice(2).join(",")`, "3,4"},
+		{`[1, 2, 3, 4].slice(3, 1).len()`, 0},
+		{`[1, 2, 3, 4].slice(-5, 10).len()`, 4},
+		{`[1, "a", true].join("-")`, "1-a-true"},
+		{`[1, "a"].sort()`, errorMessage("type mismatch: STRING < INTEGER")},
+		{`[1, 2].filter(fn(x) { x + true })`, errorMessage("type mismatch: INTEGER + BOOLEAN")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestHashMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{"a": 1, "b": 2}.keys().join(",")`, "a,b"},
+		{`{"a": 1, "b": 2}.values().join(",")`, "1,2"},
+		{`{"a": 1}.has("a")`, true},
+		{`{"a": 1}.has("b")`, false},
+		{`{"a": 1, "b": 2}.delete("a").keys().join(",")`, "b"},
+		{`let h = {"a": 1}; h.delete("a"); h.len()`, 1},
+		{`{"a": 1, "b": 2}.merge({"b": 3, "c": 4}).values().join(",")`, "1,3,4"},
+		{`{"a": 1}.has([1])`, errorMessage("unusable as hash key: ARRAY")},
+		{`{"a": 1}.merge(1)`, errorMessage("argument to `merge` must be HASH, got INTEGER")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestIntegerMethods(t *testing.T) {
+	testExpectedObject(t, testEval(`(-5).abs()`), 5)
+	testExpectedObject(t, testEval(`5.abs()`), 5)
+	testExpectedObject(t, testEval(`42.str() + "!"`), "42!")
+
+	// Values promoted to BigInt keep the integer methods
+	testExpectedObject(t, testEval(`(9223372036854775807 + 1).str()`), "9223372036854775808")
+	testExpectedObject(t, testEval(`(-9223372036854775807 - 2).abs().str()`), "9223372036854775809")
+	testExpectedObject(t, testEval(`(-9223372036854775807 - 1).abs().str()`), "9223372036854775808")
+	testExpectedObject(t, testEval(`(9223372036854775807 + 1).abs() - 1`), 9223372036854775807)
+}
+
+func TestDefineMethod(t *testing.T) {
+	shout := func(receiver object.Object, args ...object.Object) object.Object {
+		return &object.String{Value: strings.ToUpper(receiver.Inspect()) + "!"}
+	}
+
+	interp := New(WithMethod(object.STRING_OBJ, "shout", shout))
+	interp.DefineMethod(object.BOOLEAN_OBJ, "not", func(receiver object.Object, args ...object.Object) object.Object {
+		return nativeBoolToBooleanObject(receiver != TRUE)
+	})
+
+	testExpectedObject(t, evalWith(interp, `"hey".shout()`), "HEY!")
+	testExpectedObject(t, evalWith(interp, `true.not()`), false)
+	testExpectedObject(t, testEval(`"hey".shout()`), errorMessage("unknown method: STRING.shout"))
+}
+
+func TestDefinedMethodPanics(t *testing.T) {
+	double := func(receiver object.Object, args ...object.Object) object.Object {
+		return &object.Integer{Value: receiver.(*object.Integer).Value * 2}
+	}
+	interp := New(WithMethod(object.INTEGER_OBJ, "double", double))
+
+	testExpectedObject(t, evalWith(interp, `21.double()`), 42)
+	testExpectedObject(t, evalWith(interp, `99999999999999999999.double()`),
+		errorMessage("panic in `INTEGER.double`: interface conversion: object.Object is *object.BigInt, not *object.Integer"))
+}
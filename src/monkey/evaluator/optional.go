@@ -0,0 +1,69 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// Evaluates a call, index or member expression along with the chain of them on its
+// left. An optional link whose left side is null skips the rest of the chain, so
+// a?.b.c() is null when a is, and skipped reports when that happened.
+func (interp *Interpreter) evalChain(node ast.Expression, env *object.Environment) (result object.Object, skipped bool) {
+	switch node := node.(type) {
+	case *ast.CallExpression:
+		function, skipped := interp.evalChain(node.Function, env)
+		if skipped || isError(function) {
+			return function, skipped
+		}
+		if node.Optional && function == NULL {
+			return NULL, true
+		}
+
+		args, named, err := interp.evalArguments(node.Arguments, env)
+		if err != nil {
+			return err, false
+		}
+		return interp.apply(function, args, named), false
+
+	case *ast.IndexExpression:
+		left, skipped := interp.evalChain(node.Left, env)
+		if skipped || isError(left) {
+			return left, skipped
+		}
+		if node.Optional && left == NULL {
+			return NULL, true
+		}
+
+		index := interp.eval(node.Index, env)
+		if isError(index) {
+			return index, false
+		}
+		if rng, ok := index.(*object.Range); ok {
+			return interp.track(evalSliceExpression(left, rng)), false
+		}
+		return evalIndexExpression(left, index), false
+
+	case *ast.MemberExpression:
+		obj, skipped := interp.evalChain(node.Object, env)
+		if skipped || isError(obj) {
+			return obj, skipped
+		}
+		if node.Optional && obj == NULL {
+			return NULL, true
+		}
+
+		return interp.evalMemberExpression(obj, node.Member.Value), false
+
+	default:
+		return interp.eval(node, env), false
+	}
+}
+
+func (interp *Interpreter) evalCoalesceExpression(node *ast.CoalesceExpression, env *object.Environment) object.Object {
+	left := interp.eval(node.Left, env)
+	if isError(left) || left != NULL {
+		return left
+	}
+
+	return interp.eval(node.Right, env)
+}
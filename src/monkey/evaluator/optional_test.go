@@ -0,0 +1,54 @@
+package evaluator
+
+import "testing"
+
+func TestOptionalChaining(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let user = {"name": "ann"}; user?.name`, "ann"},
+		{`let user = {}["none"]; user?.name`, nil},
+		{`let user = {}; user.address?.city`, nil},
+		{`let user = {}; user.address?.city.upper()`, nil},
+		{`let user = {"address": {"city": "oslo"}}; user.address?.city.upper()`, "OSLO"},
+		{`let user = {}; user.address.city`, errorMessage("unknown method: NULL.city")},
+		{`let xs = {}["none"]; xs?[0]`, nil},
+		{`let xs = [[1, 2]]; xs[0]?[1]`, 2},
+		{`let xs = []; xs[3]?[0]`, nil},
+		{`let s = {}["none"]; s?[1..]`, nil},
+		{`let f = {}["none"]; f?.(1)`, nil},
+		{`let f = fn(x) { x * 2 }; f?.(21)`, 42},
+		{`let f = {}["none"]; f?.(puts("never"))`, nil},
+		{`let h = {}; h.missing?.trim()`, nil},
+		{`struct Point { x, y } let p = Point(1, 2); p?.x`, 1},
+		{`1?.abs()`, 1},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{}["none"] ?? 1`, 1},
+		{`2 ?? 1`, 2},
+		{`false ?? 1`, false},
+		{`0 ?? 1`, 0},
+		{`let h = {"a": 1}; h["b"] ?? h["a"]`, 1},
+		{`let h = {}; h["b"] ?? h["c"] ?? "default"`, "default"},
+		{`let user = {}; user.address?.city ?? "unknown"`, "unknown"},
+		{`if (false) { 1 } ?? 3`, 3},
+		{`1 ?? missing`, 1},
+		{`{}["none"] ?? missing`, errorMessage("identifier not found: missing")},
+		{`let x = 1 + true ?? 2`, errorMessage("type mismatch: INTEGER + BOOLEAN")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
@@ -0,0 +1,95 @@
+package evaluator
+
+import "testing"
+
+func TestDefaultParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let f = fn(x, y = 10) { x + y }; f(1)`, 11},
+		{`let f = fn(x, y = 10) { x + y }; f(1, 2)`, 3},
+		{`let f = fn(x, y = x * 2) { y }; f(4)`, 8},
+		{`let n = 5; let f = fn(x = n) { x }; let n = 6; f()`, 6},
+		{`let f = fn([a, b] = [1, 2]) { a + b }; f()`, 3},
+		{`let f = fn(x, y = 10) { x + y }; f()`, errorMessage("wrong number of arguments. got=0, want=1 to 2")},
+		{`let f = fn(x, y = 10) { x + y }; f(1, 2, 3)`, errorMessage("wrong number of arguments. got=3, want=1 to 2")},
+		{`let f = fn(x = y) { x }; f()`, errorMessage("identifier not found: y")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestRestParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let f = fn(first, ...others) { len(others) }; f(1, 2, 3)`, 2},
+		{`let f = fn(first, ...others) { len(others) }; f(1)`, 0},
+		{`let f = fn(...all) { all }; f(1, 2).join(",")`, "1,2"},
+		{`let f = fn(a, b = 2, ...c) { a + b + len(c) }; f(1)`, 3},
+		{`let f = fn(a, b = 2, ...c) { a + b + len(c) }; f(1, 5, 7, 7)`, 8},
+		{`let f = fn(first, ...others) { first }; f()`, errorMessage("wrong number of arguments. got=0, want=at least 1")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSpreadArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let add = fn(a, b, c) { a + b + c }; add(...[1, 2, 3])`, 6},
+		{`let add = fn(a, b, c) { a + b + c }; add(1, ...[2, 3])`, 6},
+		{`let add = fn(a, b, c) { a + b + c }; let xs = [1]; add(...xs, 2, ...xs)`, 4},
+		{`let f = fn(...xs) { len(xs) }; f(...[], ...[1, 2])`, 2},
+		{`len([0, ...[1, 2], 3])`, 4},
+		{`push(...[[1], 2])[1]`, 2},
+		{`let add = fn(a, b) { a + b }; add(...[1])`, errorMessage("wrong number of arguments. got=1, want=2")},
+		{`let f = fn(a) { a }; f(...5)`, errorMessage("cannot spread INTEGER")},
+		{`...[1]`, errorMessage("unexpected spread: ...[1]")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestNamedArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let f = fn(x, y) { x - y }; f(y: 1, x: 10)`, 9},
+		{`let f = fn(x, y) { x - y }; f(10, y: 1)`, 9},
+		{`let f = fn(x, y = 2, z = 3) { x + y * z }; f(1, z: 10)`, 21},
+		{`class P { fn init(self, x, y = 0) { self.s = x + y; } }; P(y: 2, x: 1).s`, 3},
+		{`class C { fn f(self, a, b) { a - b } }; C().f(b: 1, a: 3)`, 2},
+		{`let f = fn(x, y) { x }; f(1, z: 2)`, errorMessage("unknown parameter z")},
+		{`let f = fn(x, y) { x }; f(1, x: 2)`, errorMessage("multiple values for parameter x")},
+		{`let f = fn(x, y) { x }; f(y: 2)`, errorMessage("missing argument for parameter x")},
+		{`let f = fn(x, ...r) { x }; f(1, r: 2)`, errorMessage("unknown parameter r")},
+		{`class C { fn f(self) { 1 } }; C().f(self: 1)`, errorMessage("unknown parameter self")},
+		{`len(x: [1])`, errorMessage("named arguments are not supported by BUILTIN")},
+		{`class Empty {}; Empty(x: 1)`, errorMessage("wrong number of arguments. got=1, want=0")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFunctionInspectParameters(t *testing.T) {
+	evaluated := testEval(`fn(a, b = 1, ...c) { a }`)
+
+	expected := "fn(a, b = 1, ...c) {\na\n}"
+	if evaluated.Inspect() != expected {
+		t.Errorf("wrong Inspect. expected=%q, got=%q", expected, evaluated.Inspect())
+	}
+}
@@ -0,0 +1,328 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+	"strings"
+)
+
+// ===================
+// Enums
+// ===================
+func (interp *Interpreter) evalEnumStatement(node *ast.EnumStatement, env *object.Environment) object.Object {
+	enum := &object.Enum{Name: node.Name.Value}
+
+	for _, v := range node.Variants {
+		if _, ok := enum.Variant(v.Name.Value); ok {
+			return newError("duplicate variant %s in enum %s", v.Name.Value, enum.Name)
+		}
+
+		variant := &object.EnumVariant{Enum: enum, Name: v.Name.Value}
+		for _, field := range v.Fields {
+			variant.Fields = append(variant.Fields, field.Value)
+		}
+		if len(variant.Fields) == 0 {
+			variant.Unit = &object.EnumValue{Variant: variant}
+		}
+		enum.Variants = append(enum.Variants, variant)
+	}
+
+	env.Set(enum.Name, interp.track(enum))
+	return nil
+}
+
+func evalEnumMember(enum *object.Enum, name string) object.Object {
+	variant, ok := enum.Variant(name)
+	if !ok {
+		return newError("%s has no variant %s", enum.Name, name)
+	}
+
+	if variant.Unit != nil {
+		return variant.Unit
+	}
+	return variant
+}
+
+func evalEnumValueMember(value *object.EnumValue, name string) object.Object {
+	if field, ok := value.Get(name); ok {
+		return field
+	}
+
+	return newError("%s.%s has no field %s", value.Variant.Enum.Name, value.Variant.Name, name)
+}
+
+func (interp *Interpreter) constructVariant(variant *object.EnumVariant, args []object.Object) object.Object {
+	if len(args) != len(variant.Fields) {
+		return newError("wrong number of arguments. got=%d, want=%d", len(args), len(variant.Fields))
+	}
+
+	return interp.track(&object.EnumValue{Variant: variant, Values: append([]object.Object{}, args...)})
+}
+
+// ===================
+// Match
+// ===================
+func (interp *Interpreter) evalMatchExpression(node *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := interp.eval(node.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	if value, ok := subject.(*object.EnumValue); ok {
+		if err := checkExhaustive(node, value.Variant.Enum); err != nil {
+			return err
+		}
+	}
+
+	for _, arm := range node.Arms {
+		if err := interp.allocate(environmentSize); err != nil {
+			return err
+		}
+		armEnv := object.NewEnclosedEnvironment(env)
+
+		matched, err := interp.matchPattern(arm.Pattern, subject, armEnv)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard := interp.eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		return interp.eval(arm.Body, armEnv)
+	}
+
+	return newError("non-exhaustive match: no arm matches %s", subject.Inspect())
+}
+
+// Unlike match, a switch without a matching case or a default is null
+func (interp *Interpreter) evalSwitchExpression(node *ast.SwitchExpression, env *object.Environment) object.Object {
+	subject := interp.eval(node.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	for _, c := range node.Cases {
+		for _, v := range c.Values {
+			value := interp.eval(v, env)
+			if isError(value) {
+				return value
+			}
+			if objectsEqual(subject, value) {
+				return interp.eval(c.Body, env)
+			}
+		}
+	}
+
+	if node.Default != nil {
+		return interp.eval(node.Default, env)
+	}
+	return NULL
+}
+
+// Reports the variants of enum that no unguarded arm can match, and arms naming a
+// variant enum does not have, so that a missing or misspelled case fails on every run
+// rather than only when that variant comes along
+func checkExhaustive(node *ast.MatchExpression, enum *object.Enum) *object.Error {
+	covered := map[string]bool{}
+	exhaustive := false
+
+	for _, arm := range node.Arms {
+		switch pattern := arm.Pattern.(type) {
+		case *ast.WildcardPattern, *ast.BindingPattern:
+			if arm.Guard == nil {
+				exhaustive = true
+			}
+		case *ast.VariantPattern:
+			if pattern.Enum != nil && pattern.Enum.Value != enum.Name {
+				continue
+			}
+			if _, ok := enum.Variant(pattern.Name.Value); !ok {
+				return newError("%s has no variant %s", enum.Name, pattern.Name.Value)
+			}
+			if arm.Guard == nil && allIrrefutable(pattern.Arguments) {
+				covered[pattern.Name.Value] = true
+			}
+		}
+	}
+	if exhaustive {
+		return nil
+	}
+
+	missing := []string{}
+	for _, variant := range enum.Variants {
+		if !covered[variant.Name] {
+			missing = append(missing, variant.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return newError("non-exhaustive match on %s: missing %s", enum.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func allIrrefutable(patterns []ast.Pattern) bool {
+	for _, pattern := range patterns {
+		switch pattern.(type) {
+		case *ast.WildcardPattern, *ast.BindingPattern:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Binds the names in pattern to the parts of value, which must have the shape the pattern describes
+func (interp *Interpreter) destructure(pattern ast.Pattern, value object.Object, env *object.Environment) *object.Error {
+	matched, err := interp.matchPattern(pattern, value, env)
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return newError("cannot destructure %s with %s", value.Inspect(), pattern.String())
+	}
+	return nil
+}
+
+// Matches value against pattern, binding the names it introduces in env
+func (interp *Interpreter) matchPattern(pattern ast.Pattern, value object.Object, env *object.Environment) (bool, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return true, nil
+	case *ast.BindingPattern:
+		env.Set(pattern.Name.Value, value)
+		return true, nil
+	case *ast.LiteralPattern:
+		expected := interp.eval(pattern.Value, env)
+		if err, ok := expected.(*object.Error); ok {
+			return false, err
+		}
+		return objectsEqual(expected, value), nil
+	case *ast.VariantPattern:
+		return interp.matchVariantPattern(pattern, value, env)
+	case *ast.ArrayPattern:
+		return interp.matchArrayPattern(pattern, value, env)
+	case *ast.HashPattern:
+		return interp.matchHashPattern(pattern, value, env)
+	default:
+		return false, newError("unknown pattern: %s", pattern.String())
+	}
+}
+
+// Unqualified variant names are resolved in the enum of the value being matched, and
+// naming a variant it does not have is an error. A capitalized name on its own is only
+// a variant when matching an enum value; any other value it binds like a lowercase one.
+func (interp *Interpreter) matchVariantPattern(pattern *ast.VariantPattern, value object.Object, env *object.Environment) (bool, *object.Error) {
+	enumValue, ok := value.(*object.EnumValue)
+	if !ok {
+		if pattern.Enum == nil && pattern.Arguments == nil {
+			env.Set(pattern.Name.Value, value)
+			return true, nil
+		}
+		return false, nil
+	}
+
+	enum := enumValue.Variant.Enum
+	if pattern.Enum != nil && pattern.Enum.Value != enum.Name {
+		return false, nil
+	}
+	variant, ok := enum.Variant(pattern.Name.Value)
+	if !ok {
+		return false, newError("%s has no variant %s", enum.Name, pattern.Name.Value)
+	}
+	if variant != enumValue.Variant {
+		return false, nil
+	}
+
+	// Without parentheses the pattern matches the variant whatever its fields hold
+	if pattern.Arguments == nil {
+		return true, nil
+	}
+
+	if len(pattern.Arguments) != len(enumValue.Values) {
+		return false, newError("pattern %s has %d fields, but %s.%s has %d",
+			pattern.String(), len(pattern.Arguments), enumValue.Variant.Enum.Name, enumValue.Variant.Name, len(enumValue.Values))
+	}
+
+	for i, arg := range pattern.Arguments {
+		if matched, err := interp.matchPattern(arg, enumValue.Values[i], env); !matched || err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (interp *Interpreter) matchArrayPattern(pattern *ast.ArrayPattern, value object.Object, env *object.Environment) (bool, *object.Error) {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return false, nil
+	}
+
+	if len(array.Elements) < len(pattern.Elements) {
+		return false, nil
+	}
+	if pattern.Rest == nil && len(array.Elements) != len(pattern.Elements) {
+		return false, nil
+	}
+
+	for i, element := range pattern.Elements {
+		if matched, err := interp.matchPattern(element, array.Elements[i], env); !matched || err != nil {
+			return false, err
+		}
+	}
+
+	if pattern.Rest != nil && pattern.Rest.Value != "_" {
+		rest := make([]object.Object, len(array.Elements)-len(pattern.Elements))
+		copy(rest, array.Elements[len(pattern.Elements):])
+
+		restArray := interp.track(&object.Array{Elements: rest})
+		if err, ok := restArray.(*object.Error); ok {
+			return false, err
+		}
+		env.Set(pattern.Rest.Value, restArray)
+	}
+
+	return true, nil
+}
+
+func (interp *Interpreter) matchHashPattern(pattern *ast.HashPattern, value object.Object, env *object.Environment) (bool, *object.Error) {
+	for i, key := range pattern.Keys {
+		member, ok := lookupKey(value, key)
+		if !ok {
+			return false, nil
+		}
+
+		if matched, err := interp.matchPattern(pattern.Values[i], member, env); !matched || err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Looks up a string key in a hash, or a field of a struct, instance or enum value
+func lookupKey(obj object.Object, key string) (object.Object, bool) {
+	switch obj := obj.(type) {
+	case *object.Hash:
+		return obj.Get(&object.String{Value: key})
+	case *object.Struct:
+		return obj.Get(key)
+	case *object.Instance:
+		return obj.Get(key)
+	case *object.EnumValue:
+		return obj.Get(key)
+	default:
+		return nil, false
+	}
+}
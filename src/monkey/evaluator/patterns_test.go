@@ -0,0 +1,129 @@
+package evaluator
+
+import "testing"
+
+const shapeEnum = `
+enum Shape { Circle(r), Rect(w, h), Empty }
+`
+
+func TestEnums(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{shapeEnum + `Shape.Rect(2, 3).h`, 3},
+		{shapeEnum + `Shape.Circle(1) == Shape.Circle(1)`, true},
+		{shapeEnum + `Shape.Circle(1) == Shape.Circle(2)`, false},
+		{shapeEnum + `Shape.Empty == Shape.Empty`, true},
+		{shapeEnum + `Shape.Circle(1) != Shape.Empty`, true},
+		{shapeEnum + `Shape.Triangle`, errorMessage("Shape has no variant Triangle")},
+		{shapeEnum + `Shape.Circle(1).w`, errorMessage("Shape.Circle has no field w")},
+		{shapeEnum + `Shape.Rect(1)`, errorMessage("wrong number of arguments. got=1, want=2")},
+		{shapeEnum + `Shape.Empty()`, errorMessage("not a function: ENUM_VALUE")},
+		{`enum E { A, A }`, errorMessage("duplicate variant A in enum E")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestMatchEnums(t *testing.T) {
+	area := shapeEnum + `
+	let area = fn(s) {
+		match (s) {
+			Circle(r) if r > 10 -> "huge",
+			Shape.Circle(r) -> 3 * r * r,
+			Rect(w, h) -> w * h,
+			Empty -> 0
+		}
+	};
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{area + `area(Shape.Circle(2))`, 12},
+		{area + `area(Shape.Circle(11))`, "huge"},
+		{area + `area(Shape.Rect(2, 5))`, 10},
+		{area + `area(Shape.Empty)`, 0},
+		{shapeEnum + `match (Shape.Rect(1, 2)) { Circle -> 1, _ -> 2 }`, 2},
+		{shapeEnum + `match (Shape.Rect(1, 2)) { Rect -> "rect", s -> s }`, "rect"},
+		{shapeEnum + `match (Shape.Rect(1, 2)) { Rect(1, h) -> h, Rect(w, h) -> w, _ -> 0 }`, 2},
+		{shapeEnum + `match (Shape.Empty) { Circle(r) -> r, Rect(w, h) -> w }`,
+			errorMessage("non-exhaustive match on Shape: missing Empty")},
+		{shapeEnum + `match (Shape.Circle(1)) { Circle(r) if r > 5 -> r, Rect(w, h) -> w, Empty -> 0 }`,
+			errorMessage("non-exhaustive match on Shape: missing Circle")},
+		{shapeEnum + `match (Shape.Circle(1)) { Circle(a, b) -> a, _ -> 0 }`,
+			errorMessage("pattern Circle(a, b) has 2 fields, but Shape.Circle has 1")},
+		{shapeEnum + `match (Shape.Circle(1)) { Circle(r) -> r, Triangle(a) -> a, _ -> 0 }`,
+			errorMessage("Shape has no variant Triangle")},
+		{shapeEnum + `match (Shape.Circle(1)) { Circle(r) -> r, Shape.Square -> 0, _ -> 0 }`,
+			errorMessage("Shape has no variant Square")},
+		{shapeEnum + `match ([Shape.Empty]) { [Emtpy] -> 0, _ -> 1 }`, errorMessage("Shape has no variant Emtpy")},
+		{shapeEnum + `enum Other { Empty }; match (Other.Empty) { Shape.Empty -> 1, Empty -> 2 }`, 2},
+		{shapeEnum + `enum Other { Empty }; match ([Other.Empty]) { [Shape.Empty] -> 1, [Other.Empty] -> 2 }`, 2},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSwitchExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`switch (2) { case 1, 2 -> "small"; case 3 -> "three"; default -> "big" }`, "small"},
+		{`switch (3) { case 1, 2 -> "small"; case 3 -> "three"; default -> "big" }`, "three"},
+		{`switch (9) { case 1, 2 -> "small"; default -> "big" }`, "big"},
+		{`switch (9) { case 1 -> "one" }`, nil},
+		{`switch ("b") { case "a" -> 1, case "b" -> 2 }`, 2},
+		{`switch (2) { case 2.0d -> "equal" }`, "equal"},
+		{`switch ([1, 2]) { case [1, 2] -> "same" }`, "same"},
+		{`switch ({"a": 1}) { case {"a": 1} -> "same" }`, "same"},
+		{`let x = 4; switch (x * 2) { case x + 4 -> "eight" }`, "eight"},
+		{`switch (1) { default -> 0; case 1 -> 1 }`, 1},
+		{`switch (1) { case 1 -> { let y = 5; y * 2 } }`, 10},
+		{`let f = fn(x) { switch (x) { case 1 -> { return 10 } }; 20 }; f(1)`, 10},
+		{`switch (1) { case 2, missing -> 0 }`, errorMessage("identifier not found: missing")},
+		{`switch (1) { case 1, missing -> 0 }`, 0},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestMatchValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`match (1) { 0 -> "zero", 1 -> "one", _ -> "many" }`, "one"},
+		{`match (-2) { -2 -> "minus two", _ -> "other" }`, "minus two"},
+		{`match ("b") { "a" -> 1; "b" -> 2; }`, 2},
+		{`match (true) { false -> 0, true -> 1 }`, 1},
+		{`match (7) { n if n > 5 -> n * 2, n -> n }`, 14},
+		{`match (5) { x -> { let y = x * 2; y + 1 } }`, 11},
+		{`match ([1, 2, 3]) { [] -> 0, [a] -> a, [a, b, ...rest] -> len(rest) }`, 1},
+		{`match ([1, 2]) { [a, b, c] -> c, [a, 2] -> a, _ -> 0 }`, 1},
+		{`match ([1]) { [x, ..._] -> x }`, 1},
+		{`match ({"name": "ann", "age": 3}) { {"name": "bob"} -> 0, {name, age} -> name }`, "ann"},
+		{`match ({"pos": [1, 2]}) { {pos: [x, y]} -> x + y }`, 3},
+		{`match ({"a": 1}) { {b} -> b, _ -> "none" }`, "none"},
+		{`struct P { x, y }; match (P(1, 2)) { {x, y} -> x + y }`, 3},
+		{`let f = fn(x) { match (x) { 1 -> { return 10; }, _ -> 0 }; 20 }; f(1)`, 10},
+		{`match (3) { 1 -> "one", 2 -> "two" }`, errorMessage("non-exhaustive match: no arm matches 3")},
+		{`match (1) { x if y -> x }`, errorMessage("identifier not found: y")},
+		{`match (5) { Foo -> Foo * 2 }`, 10},
+		{`match ([1, 2]) { [First, Second] -> First + Second }`, 3},
+		{`let [A, b] = [1, 2]; A + b`, 3},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
@@ -0,0 +1,64 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// ===================
+// Pipes and Composition
+// ===================
+func (interp *Interpreter) evalPipeExpression(node *ast.PipeExpression, env *object.Environment) object.Object {
+	left := interp.eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	// A call on the right receives the left value ahead of its own arguments
+	call, ok := node.Right.(*ast.CallExpression)
+	if !ok {
+		fn := interp.eval(node.Right, env)
+		if isError(fn) {
+			return fn
+		}
+		return interp.applyFunction(fn, []object.Object{left})
+	}
+
+	fn := interp.eval(call.Function, env)
+	if isError(fn) {
+		return fn
+	}
+
+	args, named, err := interp.evalArguments(call.Arguments, env)
+	if err != nil {
+		return err
+	}
+
+	return interp.apply(fn, append([]object.Object{left}, args...), named)
+}
+
+// Composes two functions into one that passes its arguments to first and the result to second
+func (interp *Interpreter) evalComposition(first object.Object, second object.Object) object.Object {
+	for _, fn := range []object.Object{first, second} {
+		if !isCallable(fn) {
+			return newError("cannot compose %s", fn.Type())
+		}
+	}
+
+	return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+		result := interp.applyFunction(first, args)
+		if isError(result) {
+			return result
+		}
+		return interp.applyFunction(second, []object.Object{result})
+	}}
+}
+
+func isCallable(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Function, *object.Builtin, *object.BoundMethod, *object.Class, *object.EnumVariant, *object.StructType:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,51 @@
+package evaluator
+
+import "testing"
+
+func TestPipeExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let double = fn(x) { x * 2 }; 3 |> double`, 6},
+		{`let sub = fn(a, b) { a - b }; 10 |> sub(3)`, 7},
+		{`let double = fn(x) { x * 2 }; let inc = fn(x) { x + 1 }; 3 |> double |> inc`, 7},
+		{`let double = fn(x) { x * 2 }; 1 + 2 |> double`, 6},
+		{`let double = fn(x) { x * 2 }; (3 |> double) == 6`, true},
+		{`[1, 2, 3] |> len`, 3},
+		{`[1, 2] |> push(3) |> len`, 3},
+		{`[3, 1, 2] |> rest |> first`, 1},
+		{`let f = fn(x, y = 1, z = 0) { x * y + z }; 2 |> f(z: 5)`, 7},
+		{`let f = fn(a, b, c) { a + b + c }; 1 |> f(...[2, 3])`, 6},
+		{`[1, 2, 3] |> fn(xs) { xs.map(fn(x) { x * x }) } |> len`, 3},
+		{`let obj = {"twice": fn(x) { x * 2 }}; 4 |> obj.twice`, 8},
+		{`1 |> 2`, errorMessage("not a function: INTEGER")},
+		{`1 |> missing`, errorMessage("identifier not found: missing")},
+		{`let sub = fn(a, b) { a - b }; 1 |> sub`, errorMessage("wrong number of arguments. got=1, want=2")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestComposition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let double = fn(x) { x * 2 }; let inc = fn(x) { x + 1 }; (double >> inc)(5)`, 11},
+		{`let double = fn(x) { x * 2 }; let inc = fn(x) { x + 1 }; (inc >> double)(5)`, 12},
+		{`let double = fn(x) { x * 2 }; let inc = fn(x) { x + 1 }; let f = double >> inc >> double; f(1)`, 6},
+		{`let add = fn(a, b) { a + b }; let inc = fn(x) { x + 1 }; (add >> inc)(1, 2)`, 4},
+		{`(rest >> len)([1, 2, 3])`, 2},
+		{`let double = fn(x) { x * 2 }; 5 |> double >> double`, 20},
+		{`let double = fn(x) { x * 2 }; [1, 2].map(double >> double).join(",")`, "4,8"},
+		{`let f = fn(x) { x }; f >> 1`, errorMessage("cannot compose INTEGER")},
+		{`let f = fn(x) { x }; (f >> len)(1)`, errorMessage("argument to `len` not supported, got INTEGER")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
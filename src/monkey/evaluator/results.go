@@ -0,0 +1,104 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// Result is the enum of the values made by ok() and err(), which programs can match
+// like any other: Ok(value) for a success and Err(error) for a failure. It never
+// changes, so every interpreter shares it.
+var (
+	resultEnum = &object.Enum{Name: "Result"}
+	resultOk   = &object.EnumVariant{Enum: resultEnum, Name: "Ok", Fields: []string{"value"}}
+	resultErr  = &object.EnumVariant{Enum: resultEnum, Name: "Err", Fields: []string{"error"}}
+)
+
+func init() {
+	resultEnum.Variants = []*object.EnumVariant{resultOk, resultErr}
+}
+
+// Returns obj as a result, if it is one
+func asResult(obj object.Object) (*object.EnumValue, bool) {
+	result, ok := obj.(*object.EnumValue)
+	if !ok || result.Variant.Enum != resultEnum {
+		return nil, false
+	}
+	return result, true
+}
+
+func (interp *Interpreter) evalPropagateExpression(node *ast.PropagateExpression, env *object.Environment) object.Object {
+	value := interp.eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	result, ok := asResult(value)
+	if !ok {
+		return newTypeError("operator ? needs a Result, got %s", value.Type())
+	}
+
+	if result.Variant == resultErr {
+		return &object.Error{Message: "? outside of a function", Return: result}
+	}
+	return result.Values[0]
+}
+
+// ok(value) wraps a value in a successful result
+func builtinOk(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	return &object.EnumValue{Variant: resultOk, Values: []object.Object{args[0]}}
+}
+
+// err(error) wraps an error, usually a string, in a failed result
+func builtinErr(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	return &object.EnumValue{Variant: resultErr, Values: []object.Object{args[0]}}
+}
+
+// is_ok(result) reports whether a result is a success
+func builtinIsOk(args ...object.Object) object.Object {
+	result, err := resultArgument("is_ok", args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(result.Variant == resultOk)
+}
+
+// is_err(result) reports whether a result is a failure
+func builtinIsErr(args ...object.Object) object.Object {
+	result, err := resultArgument("is_err", args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(result.Variant == resultErr)
+}
+
+// unwrap(result) returns the value of a successful result, and raises the error of a
+// failed one
+func builtinUnwrap(args ...object.Object) object.Object {
+	result, err := resultArgument("unwrap", args)
+	if err != nil {
+		return err
+	}
+
+	if result.Variant == resultErr {
+		return &object.Error{Message: "unwrap of err: " + result.Values[0].Inspect(), Kind: "UnwrapError"}
+	}
+	return result.Values[0]
+}
+
+func resultArgument(name string, args []object.Object) (*object.EnumValue, *object.Error) {
+	if len(args) != 1 {
+		return nil, newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	result, ok := asResult(args[0])
+	if !ok {
+		return nil, newError("argument to `%s` must be a Result, got %s", name, args[0].Type())
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package evaluator
+
+import "testing"
+
+const parseAge = `
+let parseAge = fn(n) {
+	if (n < 0) { return err("negative age") }
+	ok(n)
+};
+`
+
+func TestResults(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`is_ok(ok(1))`, true},
+		{`is_ok(err("boom"))`, false},
+		{`is_err(err("boom"))`, true},
+		{`unwrap(ok(5))`, 5},
+		{`ok(5).value`, 5},
+		{`err("boom").error`, "boom"},
+		{`ok(1) == ok(1)`, true},
+		{`ok(1) == err(1)`, false},
+		{`unwrap(err("boom"))`, errorMessage("unwrap of err: boom")},
+		{`try { unwrap(err("boom")) } catch (e) { e.kind }`, "UnwrapError"},
+		{`is_ok(1)`, errorMessage("argument to `is_ok` must be a Result, got INTEGER")},
+		{`ok()`, errorMessage("wrong number of arguments. got=0, want=1")},
+		{`match (ok(2)) { Ok(v) -> v * 10, Err(e) -> 0 }`, 20},
+		{`match (err("x")) { Result.Ok(v) -> v, Result.Err(e) -> e }`, "x"},
+		{`match (ok(2)) { Ok(v) -> v }`, errorMessage("non-exhaustive match on Result: missing Err")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestPropagateOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{parseAge + `let next = fn(n) { ok(parseAge(n)? + 1) }; unwrap(next(41))`, 42},
+		{parseAge + `let next = fn(n) { ok(parseAge(n)? + 1) }; next(-1).error`, "negative age"},
+		{parseAge + `let total = fn(a, b) { ok(parseAge(a)? + parseAge(b)?) }; is_err(total(1, -2))`, true},
+		{parseAge + `let total = fn(a, b) { ok(parseAge(a)? + parseAge(b)?) }; unwrap(total(1, 2))`, 3},
+		{`let first = fn(r) { (r?).value }; first(ok(ok(7)))`, 7},
+		{`let f = fn() { 1? }; f()`, errorMessage("operator ? needs a Result, got INTEGER")},
+		{`let f = fn() { try { err("x")? } catch (e) { "caught" } }; f().error`, "x"},
+		{`let f = fn() { try { err("x")? } finally { puts } }; f().error`, "x"},
+		{`let f = fn() { [ok(1), err("x")].map(fn(r) { r? }) }; f()[1].error`, "x"},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestPropagateAtTopLevel(t *testing.T) {
+	evaluated := testEval(`err("top")?; 1`)
+	result, ok := asResult(evaluated)
+	if !ok || result.Variant != resultErr {
+		t.Fatalf("expected the program to end with the err result. got=%s", evaluated.Inspect())
+	}
+	testExpectedObject(t, result.Values[0], "top")
+}
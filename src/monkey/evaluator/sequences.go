@@ -0,0 +1,377 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// ===================
+// Ranges
+// ===================
+func (interp *Interpreter) evalRangeExpression(node *ast.RangeExpression, env *object.Environment) object.Object {
+	rng := &object.Range{Step: 1, Inclusive: node.Inclusive}
+
+	bounds := []struct {
+		exp   ast.Expression
+		value *int64
+		set   *bool
+	}{
+		{node.Start, &rng.Start, &rng.HasStart},
+		{node.End, &rng.End, &rng.HasEnd},
+		{node.Step, &rng.Step, nil},
+	}
+
+	for _, bound := range bounds {
+		if bound.exp == nil {
+			continue
+		}
+
+		value := interp.eval(bound.exp, env)
+		if isError(value) {
+			return value
+		}
+		integer, ok := value.(*object.Integer)
+		if !ok {
+			return newError("range bounds must be INTEGER, got %s", value.Type())
+		}
+
+		*bound.value = integer.Value
+		if bound.set != nil {
+			*bound.set = true
+		}
+	}
+
+	if rng.Step == 0 {
+		return newError("range step must not be zero")
+	}
+
+	return interp.track(rng)
+}
+
+// Slices an array or string by the indices of a range. Bounds beyond either end
+// are clamped, open bounds run to the end in the direction of the step, and a
+// negative step walks backwards.
+func sliceIndices(rng *object.Range, length int64) []int64 {
+	var start, end int64
+	if rng.Step > 0 {
+		start, end = 0, length
+		if rng.HasStart {
+			start = min(max(rng.Start, 0), length)
+		}
+		if rng.HasEnd {
+			// Clamp before including the end, which could overflow at MaxInt64
+			end = min(max(rng.End, -1), length)
+			if rng.Inclusive {
+				end++
+			}
+			end = min(max(end, 0), length)
+		}
+	} else {
+		start, end = length-1, -1
+		if rng.HasStart {
+			start = min(max(rng.Start, -1), length-1)
+		}
+		if rng.HasEnd {
+			end = min(max(rng.End, -1), length)
+			if rng.Inclusive {
+				end--
+			}
+			end = min(max(end, -1), length-1)
+		}
+	}
+
+	indices := []int64{}
+	for i := start; (rng.Step > 0 && i < end) || (rng.Step < 0 && i > end); i += rng.Step {
+		indices = append(indices, i)
+
+		// Stop instead of stepping past the end, which could overflow with a large step
+		remaining, step := uint64(end-i), uint64(rng.Step)
+		if rng.Step < 0 {
+			remaining, step = uint64(i-end), uint64(-rng.Step)
+		}
+		if remaining <= step {
+			break
+		}
+	}
+	return indices
+}
+
+func evalSliceExpression(left object.Object, rng *object.Range) object.Object {
+	switch left := left.(type) {
+	case *object.Array:
+		indices := sliceIndices(rng, int64(len(left.Elements)))
+		elements := make([]object.Object, len(indices))
+		for i, idx := range indices {
+			elements[i] = left.Elements[idx]
+		}
+		return &object.Array{Elements: elements}
+	case *object.String:
+		// Strings are sliced by character, as for-in iterates them
+		chars := []rune(left.Value)
+		indices := sliceIndices(rng, int64(len(chars)))
+		if rng.Step == 1 && len(indices) > 0 {
+			return &object.String{Value: string(chars[indices[0] : indices[len(indices)-1]+1])}
+		}
+		value := make([]rune, len(indices))
+		for i, idx := range indices {
+			value[i] = chars[idx]
+		}
+		return &object.String{Value: string(value)}
+	default:
+		return newError("slice operator not supported: %s", left.Type())
+	}
+}
+
+// ===================
+// Iteration
+// ===================
+
+// Returns a function that starts a fresh iteration over the elements of obj each
+// time it is called, or an error if obj cannot be iterated
+func (interp *Interpreter) iterable(obj object.Object) (func() object.Iterator, *object.Error) {
+	switch obj := obj.(type) {
+	case *object.Array:
+		return func() object.Iterator { return sliceIterator(obj.Elements) }, nil
+	case *object.String:
+		return func() object.Iterator {
+			chars := []object.Object{}
+			for _, ch := range obj.Value {
+				chars = append(chars, &object.String{Value: string(ch)})
+			}
+			return sliceIterator(chars)
+		}, nil
+	case *object.Hash:
+		return func() object.Iterator {
+			keys := []object.Object{}
+			for _, pair := range obj.Entries() {
+				keys = append(keys, pair.Key)
+			}
+			return sliceIterator(keys)
+		}, nil
+	case *object.Range:
+		if !obj.HasStart {
+			return nil, newError("cannot iterate over a range without a start")
+		}
+		return obj.Iterate, nil
+	case *object.Sequence:
+		return obj.Iterate, nil
+	case *object.Generator:
+		return func() object.Iterator { return obj }, nil
+	default:
+		return nil, newError("cannot iterate over %s", obj.Type())
+	}
+}
+
+func sliceIterator(elements []object.Object) object.Iterator {
+	i := 0
+	return object.IteratorFunc(func() (object.Object, bool) {
+		if i >= len(elements) {
+			return nil, false
+		}
+		i++
+		return elements[i-1], true
+	})
+}
+
+// Collects every element of obj into a slice, counting each one as an evaluation step
+// so that endless sequences run into the step limit or the deadline
+func (interp *Interpreter) collect(obj object.Object) ([]object.Object, *object.Error) {
+	iterate, err := interp.iterable(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := []object.Object{}
+	for iter := iterate(); ; {
+		if err := interp.checkContext(); err != nil {
+			return nil, err
+		}
+		if err := interp.step(); err != nil {
+			return nil, err
+		}
+
+		element, ok := iter.Next()
+		if !ok {
+			return elements, nil
+		}
+		if err, ok := element.(*object.Error); ok {
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+}
+
+func (interp *Interpreter) evalForExpression(node *ast.ForExpression, env *object.Environment) object.Object {
+	iterable := interp.eval(node.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	iterate, err := interp.iterable(iterable)
+	if err != nil {
+		return err
+	}
+
+	for iter := iterate(); ; {
+		if err := interp.checkContext(); err != nil {
+			return err
+		}
+
+		element, ok := iter.Next()
+		if !ok {
+			return NULL
+		}
+		if isError(element) {
+			return element
+		}
+
+		// Each iteration gets its own scope, so closures capture that iteration's element
+		if err := interp.allocate(environmentSize); err != nil {
+			return err
+		}
+		loopEnv := object.NewEnclosedEnvironment(env)
+		if err := interp.destructure(node.Pattern, element, loopEnv); err != nil {
+			return err
+		}
+
+		result := interp.eval(node.Body, loopEnv)
+		if result != nil {
+			rt := result.Type()
+			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+				return result
+			}
+		}
+	}
+}
+
+// ===================
+// Sequence Methods
+// ===================
+func (interp *Interpreter) sequenceMap(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	iterate, err := interp.iterable(receiver)
+	if err != nil {
+		return err
+	}
+
+	return &object.Sequence{Iterate: func() object.Iterator {
+		iter := iterate()
+		return object.IteratorFunc(func() (object.Object, bool) {
+			element, ok := iter.Next()
+			if !ok || isError(element) {
+				return element, ok
+			}
+			return interp.applyFunction(args[0], []object.Object{element}), true
+		})
+	}}
+}
+
+func (interp *Interpreter) sequenceFilter(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	iterate, err := interp.iterable(receiver)
+	if err != nil {
+		return err
+	}
+
+	return &object.Sequence{Iterate: func() object.Iterator {
+		iter := iterate()
+		return object.IteratorFunc(func() (object.Object, bool) {
+			for {
+				element, ok := iter.Next()
+				if !ok || isError(element) {
+					return element, ok
+				}
+
+				result := interp.applyFunction(args[0], []object.Object{element})
+				if isError(result) {
+					return result, true
+				}
+				if isTruthy(result) {
+					return element, true
+				}
+			}
+		})
+	}}
+}
+
+func (interp *Interpreter) sequenceTake(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	count, ok := args[0].(*object.Integer)
+	if !ok || count.Value < 0 {
+		return newError("argument to `take` must be a non-negative INTEGER, got %s", args[0].Inspect())
+	}
+	iterate, err := interp.iterable(receiver)
+	if err != nil {
+		return err
+	}
+
+	return &object.Sequence{Iterate: func() object.Iterator {
+		iter, taken := iterate(), int64(0)
+		return object.IteratorFunc(func() (object.Object, bool) {
+			if taken >= count.Value {
+				return nil, false
+			}
+			taken++
+			return iter.Next()
+		})
+	}}
+}
+
+func (interp *Interpreter) sequenceZip(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	iterateLeft, err := interp.iterable(receiver)
+	if err != nil {
+		return err
+	}
+	iterateRight, err := interp.iterable(args[0])
+	if err != nil {
+		return err
+	}
+
+	return &object.Sequence{Iterate: func() object.Iterator {
+		left, right := iterateLeft(), iterateRight()
+		return object.IteratorFunc(func() (object.Object, bool) {
+			a, ok := left.Next()
+			if !ok || isError(a) {
+				return a, ok
+			}
+			b, ok := right.Next()
+			if !ok || isError(b) {
+				return b, ok
+			}
+			return interp.track(&object.Array{Elements: []object.Object{a, b}}), true
+		})
+	}}
+}
+
+func (interp *Interpreter) sequenceToArray(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+
+	elements, err := interp.collect(receiver)
+	if err != nil {
+		return err
+	}
+	return &object.Array{Elements: elements}
+}
+
+func rangeLen(receiver object.Object, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+
+	rng := receiver.(*object.Range)
+	if !rng.HasStart || !rng.HasEnd {
+		return newError("range without a start or end has no length")
+	}
+	return normalizeBigInt(rng.Len())
+}
@@ -0,0 +1,156 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"monkey/object"
+	"testing"
+	"time"
+)
+
+func TestRanges(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(1..10).len()`, 9},
+		{`(1..=10).len()`, 10},
+		{`(0..10 step 3).len()`, 4},
+		{`(10..0 step -2).len()`, 5},
+		{`(10..=0 step -5).toArray().join(",")`, "10,5,0"},
+		{`(5..1).len()`, 0},
+		{`(0..=9223372036854775806).len()`, 9223372036854775807},
+		{`(0..=9223372036854775807).len().str()`, "9223372036854775808"},
+		{`(-9223372036854775807 - 1..9223372036854775807).len().str()`, "18446744073709551615"},
+		{`(-9223372036854775807 - 1..=9223372036854775807).len().str()`, "18446744073709551616"},
+		{`(9223372036854775807..=-9223372036854775807 - 1 step -1).len().str()`, "18446744073709551616"},
+		{`(1..4).toArray().join(",")`, "1,2,3"},
+		{`let n = 3; (n - 1..n * 2).toArray().join(",")`, "2,3,4,5"},
+		{`(1..).take(3).toArray().join(",")`, "1,2,3"},
+		{`(9223372036854775806..).toArray().len()`, 2},
+		{`(1.."a")`, errorMessage("range bounds must be INTEGER, got STRING")},
+		{`(1..5 step 0)`, errorMessage("range step must not be zero")},
+		{`(..5).toArray()`, errorMessage("cannot iterate over a range without a start")},
+		{`(1..).len()`, errorMessage("range without a start or end has no length")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestRangeInspect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`1..10`, "1..10"},
+		{`1..=10 step 2`, "1..=10 step 2"},
+		{`..5`, "..5"},
+		{`2..`, "2.."},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong Inspect. expected=%q, got=%q", tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestSlicing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[0, 1, 2, 3, 4][1..3].join(",")`, "1,2"},
+		{`[0, 1, 2, 3, 4][1..=3].join(",")`, "1,2,3"},
+		{`[0, 1, 2, 3, 4][..2].join(",")`, "0,1"},
+		{`[0, 1, 2, 3, 4][3..].join(",")`, "3,4"},
+		{`[0, 1, 2, 3, 4][0.. step 2].join(",")`, "0,2,4"},
+		{`[0, 1, 2, 3, 4][.. step -1].join(",")`, "4,3,2,1,0"},
+		{`[0, 1, 2, 3, 4][3..1 step -1].join(",")`, "3,2"},
+		{`[0, 1, 2][-5..10].join(",")`, "0,1,2"},
+		{`[0, 1, 2][2..1].len()`, 0},
+		{`"hello world"[..5]`, "hello"},
+		{`"hello world"[6..]`, "world"},
+		{`"hello"[.. step -1]`, "olleh"},
+		{`"héllo"[0..2]`, "hé"},
+		{`"héllo wörld"[6..]`, "wörld"},
+		{`"héllo"[.. step -1]`, "olléh"},
+		{`[1, 2, 3, 4, 5][4..=-9223372036854775808 step -1].join(",")`, "5,4,3,2,1"},
+		{`[1, 2, 3, 4, 5][0..=9223372036854775807].join(",")`, "1,2,3,4,5"},
+		{`[1, 2, 3][1.. step 9223372036854775807].join(",")`, "2"},
+		{`[1, 2, 3][1.. step -9223372036854775807 - 1].join(",")`, "2"},
+		{`"hello"[3..2]`, ""},
+		{`5[1..2]`, errorMessage("slice operator not supported: INTEGER")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestForExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`struct Acc { n }; let acc = Acc(0); for (i in 1..=100) { acc.n = acc.n + i }; acc.n`, 5050},
+		{`struct Acc { s }; let acc = Acc(""); for (x in ["a", "b"]) { acc.s = acc.s + x }; acc.s`, "ab"},
+		{`struct Acc { s }; let acc = Acc(""); for (c in "hey") { acc.s = c + acc.s }; acc.s`, "yeh"},
+		{`struct Acc { s }; let acc = Acc(""); for (k in {"a": 1, "b": 2}) { acc.s = acc.s + k }; acc.s`, "ab"},
+		{`struct Acc { n }; let acc = Acc(0); for ([a, b] in [[1, 2], [3, 4]]) { acc.n = acc.n + a * b }; acc.n`, 14},
+		{`let f = fn() { for (i in 1..) { if (i > 3) { return i; } } }; f()`, 4},
+		{`let fs = (1..4).map(fn(i) { fn() { i } }).toArray(); fs[1]()`, 2},
+		{`for (i in 1..3) { i }`, nil},
+		{`let x = 10; for (x in 1..3) { x }; x`, 10},
+		{`for (i in 5) { i }`, errorMessage("cannot iterate over INTEGER")},
+		{`for ([a, b] in [1]) { a }`, errorMessage("cannot destructure 1 with [a, b]")},
+		{`for (i in 1..3) { i + true }`, errorMessage("type mismatch: INTEGER + BOOLEAN")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestLazySequences(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(1..=5).map(fn(x) { x * x }).toArray().join(",")`, "1,4,9,16,25"},
+		{`(1..=10).filter(fn(x) { x > 7 }).toArray().join(",")`, "8,9,10"},
+		{`(1..).map(fn(x) { x * 2 }).filter(fn(x) { x > 10 }).take(2).toArray().join(",")`, "12,14"},
+		{`(1..).zip(["a", "b"]).toArray()[1][1]`, "b"},
+		{`(1..3).zip(10..).map(fn([a, b]) { a + b }).toArray().join(",")`, "11,13"},
+		{`let evens = (1..).filter(fn(x) { x / 2 * 2 == x }); evens.take(2).toArray().join(",") + evens.take(1).toArray().join(",")`, "2,42"},
+		{`(1..4).take(10).toArray().len()`, 3},
+		{`[...(1..=3), ...(1..3).map(fn(x) { 0 })].len()`, 5},
+		{`let f = fn(a, b) { a + b }; f(...(1..3))`, 3},
+		{`(1..).map(fn(x) { x + true }).take(1).toArray()`, errorMessage("type mismatch: INTEGER + BOOLEAN")},
+		{`(1..3).take(-1)`, errorMessage("argument to `take` must be a non-negative INTEGER, got -1")},
+		{`(1..3).zip(5)`, errorMessage("cannot iterate over INTEGER")},
+		{`f(...5)`, errorMessage("identifier not found: f")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestEndlessSequencesRespectLimits(t *testing.T) {
+	evaluated := evalWith(New(WithLimits(Limits{MaxSteps: 1000})), `(1..).toArray()`)
+	if !errors.Is(evaluated.(*object.Error), ErrLimitExceeded) {
+		t.Errorf("expected a step limit error. got=%s", evaluated.Inspect())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	evaluated = evalWithContext(ctx, New(), `for (i in 1..) { }`)
+	if !errors.Is(evaluated.(*object.Error), context.DeadlineExceeded) {
+		t.Errorf("expected a timeout. got=%s", evaluated.Inspect())
+	}
+}
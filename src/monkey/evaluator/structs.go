@@ -0,0 +1,92 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// ===================
+// Structs
+// ===================
+func (interp *Interpreter) evalStructStatement(node *ast.StructStatement, env *object.Environment) object.Object {
+	def := &object.StructType{Name: node.Name.Value}
+	for _, field := range node.Fields {
+		if def.FieldIndex(field.Value) >= 0 {
+			return newError("duplicate field %s in struct %s", field.Value, def.Name)
+		}
+		def.Fields = append(def.Fields, field.Value)
+	}
+
+	env.Set(def.Name, interp.track(def))
+	return nil
+}
+
+func (interp *Interpreter) evalStructLiteral(node *ast.StructLiteral, env *object.Environment) object.Object {
+	obj := interp.evalIdentifier(node.Name, env)
+	if isError(obj) {
+		return obj
+	}
+	def, ok := obj.(*object.StructType)
+	if !ok {
+		return newError("not a struct: %s", obj.Type())
+	}
+
+	values := make([]object.Object, len(def.Fields))
+	for i, field := range node.Fields {
+		idx := def.FieldIndex(field.Value)
+		if idx < 0 {
+			return newError("unknown field %s for struct %s", field.Value, def.Name)
+		}
+		if values[idx] != nil {
+			return newError("duplicate field %s for struct %s", field.Value, def.Name)
+		}
+
+		value := interp.eval(node.Values[i], env)
+		if isError(value) {
+			return value
+		}
+		values[idx] = value
+	}
+
+	return interp.newStruct(def, values)
+}
+
+func (interp *Interpreter) newStruct(def *object.StructType, values []object.Object) object.Object {
+	for i, value := range values {
+		if value == nil {
+			return newError("missing field %s for struct %s", def.Fields[i], def.Name)
+		}
+	}
+
+	return interp.track(&object.Struct{Definition: def, Values: values})
+}
+
+func (interp *Interpreter) evalAssignExpression(node *ast.AssignExpression, env *object.Environment) object.Object {
+	obj := interp.eval(node.Target.Object, env)
+	if isError(obj) {
+		return obj
+	}
+
+	value := interp.eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	switch obj := obj.(type) {
+	case *object.Struct:
+		if !obj.Set(node.Target.Member.Value, value) {
+			return newError("%s has no field %s", obj.Definition.Name, node.Target.Member.Value)
+		}
+	case *object.Instance:
+		if _, ok := obj.Get(node.Target.Member.Value); !ok {
+			if err := interp.allocate(hashPairSize); err != nil {
+				return err
+			}
+		}
+		obj.Set(node.Target.Member.Value, value)
+	default:
+		return newError("cannot assign to member of %s", obj.Type())
+	}
+
+	return value
+}
@@ -0,0 +1,50 @@
+package evaluator
+
+import "testing"
+
+func TestStructs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`struct Point { x, y }; let p = Point{x: 1, y: 2}; p.x + p.y`, 3},
+		{`struct Point { x, y }; let p = Point(3, 4); p.y`, 4},
+		{`struct Point { x, y }; let p = Point{y: 2, x: 1}; p.x = 10; p.x`, 10},
+		{`struct Point { x, y }; let p = Point(1, 2); let q = p; q.x = 5; p.x`, 5},
+		{`struct Point { x, y }; Point(1, 2) == Point{x: 1, y: 2}`, true},
+		{`struct Point { x, y }; Point(1, 2) != Point(2, 1)`, true},
+		{`struct Point { x, y }; struct Pair { x, y }; Point(1, 2) == Pair(1, 2)`, false},
+		{`struct Box { items }; Box([1, {"a": 2}]) == Box([1, {"a": 2}])`, true},
+		{`struct Point { x, y }; Point{x: 1}`, errorMessage("missing field y for struct Point")},
+		{`struct Point { x, y }; Point{x: 1, y: 2, z: 3}`, errorMessage("unknown field z for struct Point")},
+		{`struct Point { x, y }; Point{x: 1, x: 2}`, errorMessage("duplicate field x for struct Point")},
+		{`struct Point { x, x }`, errorMessage("duplicate field x in struct Point")},
+		{`struct Point { x, y }; Point(1)`, errorMessage("wrong number of arguments. got=1, want=2")},
+		{`struct Point { x, y }; Point(1, 2).z`, errorMessage("Point has no field z")},
+		{`struct Point { x, y }; let p = Point(1, 2); p.z = 3`, errorMessage("Point has no field z")},
+		{`struct Point { x, y }; Point(1, 2) < Point(1, 2)`, errorMessage("unknown operator: STRUCT < STRUCT")},
+		{`let h = {"a": 1}; h.a = 2`, errorMessage("cannot assign to member of HASH")},
+		{`let Point = 1; Point{x: 1}`, errorMessage("not a struct: INTEGER")},
+	}
+
+	for _, tt := range tests {
+		testExpectedObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestStructInspect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`struct Point { x, y }; Point{x: 1, y: 2}`, "Point{x: 1, y: 2}"},
+		{`struct Point { x, y }; Point`, "struct Point { x, y }"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong Inspect. expected=%q, got=%q", tt.expected, evaluated.Inspect())
+		}
+	}
+}
@@ -1,3 +1,3 @@
 module monkey
 
-go 1.23.1
\ No newline at end of file
+go 1.25
\ No newline at end of file
@@ -1,6 +1,9 @@
 package lexer
 
-import "monkey/token"
+import (
+	"monkey/token"
+	"strings"
+)
 
 type Lexer struct {
 	input        string
@@ -19,7 +22,9 @@ func New(input string) *Lexer {
 func (lex *Lexer) NextToken() token.Token {
 	var tok token.Token
 
+	start := lex.position
 	lex.skipWhiteSpace()
+	spaced := lex.position != start
 
 	switch lex.ch {
 	case '=':
@@ -34,7 +39,12 @@ func (lex *Lexer) NextToken() token.Token {
 	case '+':
 		tok = newToken(token.PLUS, lex.ch)
 	case '-':
-		tok = newToken(token.MINUS, lex.ch)
+		if lex.peekChar() == '>' {
+			lex.readChar()
+			tok = token.Token{Type: token.ARROW, Literal: "->"}
+		} else {
+			tok = newToken(token.MINUS, lex.ch)
+		}
 	case '!':
 		if lex.peekChar() == '=' {
 			initialCh := lex.ch
@@ -51,11 +61,59 @@ func (lex *Lexer) NextToken() token.Token {
 	case '<':
 		tok = newToken(token.LT, lex.ch)
 	case '>':
-		tok = newToken(token.GT, lex.ch)
+		if lex.peekChar() == '>' {
+			lex.readChar()
+			tok = token.Token{Type: token.COMPOSE, Literal: ">>"}
+		} else {
+			tok = newToken(token.GT, lex.ch)
+		}
+	case '|':
+		if lex.peekChar() == '>' {
+			lex.readChar()
+			tok = token.Token{Type: token.PIPE, Literal: "|>"}
+		} else {
+			tok = newToken(token.ILLEGAL, lex.ch)
+		}
+	case '?':
+		// Like in Swift, a ? after whitespace is the conditional operator and one
+		// straight after an operand is postfix, as in f(x)? and a?.b
+		switch {
+		case lex.peekChar() == '?':
+			lex.readChar()
+			tok = token.Token{Type: token.COALESCE, Literal: "??"}
+		case spaced:
+			tok = newToken(token.TERNARY, lex.ch)
+		case lex.peekChar() == '.':
+			lex.readChar()
+			tok = token.Token{Type: token.OPTIONAL_DOT, Literal: "?."}
+		case lex.peekChar() == '[':
+			lex.readChar()
+			tok = token.Token{Type: token.OPTIONAL_INDEX, Literal: "?["}
+		default:
+			tok = newToken(token.QUESTION, lex.ch)
+		}
 	case ',':
 		tok = newToken(token.COMMA, lex.ch)
 	case ';':
 		tok = newToken(token.SEMICOLON, lex.ch)
+	case ':':
+		tok = newToken(token.COLON, lex.ch)
+	case '.':
+		switch {
+		case lex.peekChar() == '.' && lex.peekCharAt(1) == '.':
+			lex.readChar()
+			lex.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+		case lex.peekChar() == '.' && lex.peekCharAt(1) == '=':
+			lex.readChar()
+			lex.readChar()
+			tok = token.Token{Type: token.RANGE_INCLUSIVE, Literal: "..="}
+		case lex.peekChar() == '.':
+			lex.readChar()
+			tok = token.Token{Type: token.RANGE, Literal: ".."}
+		default:
+			tok = newToken(token.DOT, lex.ch)
+		}
 	case '(':
 		tok = newToken(token.LPAREN, lex.ch)
 	case ')':
@@ -64,6 +122,22 @@ func (lex *Lexer) NextToken() token.Token {
 		tok = newToken(token.LBRACE, lex.ch)
 	case '}':
 		tok = newToken(token.RBRACE, lex.ch)
+	case '[':
+		tok = newToken(token.LBRACKET, lex.ch)
+	case ']':
+		tok = newToken(token.RBRACKET, lex.ch)
+	case '"':
+		literal, template := lex.readString()
+		tok.Type = token.STRING
+		tok.Literal = unescape(literal)
+		if template {
+			// Escapes are only resolved in the text around interpolations, by SplitTemplate
+			tok.Type = token.TEMPLATE
+			tok.Literal = literal
+		}
+	case '`':
+		tok.Type = token.STRING
+		tok.Literal = lex.readRawString()
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
@@ -73,9 +147,7 @@ func (lex *Lexer) NextToken() token.Token {
 			tok.Type = token.LookupIdent(tok.Literal)
 			return tok
 		} else if isDigit(lex.ch) {
-			tok.Literal = lex.readNumber()
-			tok.Type = token.INT
-			return tok
+			return lex.readNumber()
 		} else {
 			tok = newToken(token.ILLEGAL, lex.ch)
 		}
@@ -98,10 +170,15 @@ func (l *Lexer) readChar() {
 
 // Get the next character without advancing position
 func (lex *Lexer) peekChar() byte {
-	if lex.readPosition >= len(lex.input) {
+	return lex.peekCharAt(0)
+}
+
+// Get the character offset places past the next one without advancing position
+func (lex *Lexer) peekCharAt(offset int) byte {
+	if lex.readPosition+offset >= len(lex.input) {
 		return 0
 	}
-	return lex.input[lex.readPosition]
+	return lex.input[lex.readPosition+offset]
 }
 
 func isDigit(ch byte) bool {
@@ -120,14 +197,148 @@ func (lex *Lexer) readIdentifier() string {
 	return lex.input[initialPosition:lex.position]
 }
 
-func (lex *Lexer) readNumber() string {
+// Reads an integer such as 42 or a decimal such as 12.50d. A fractional part
+// without the d suffix is not a valid number.
+func (lex *Lexer) readNumber() token.Token {
 	initialPosition := lex.position
+	lex.readDigits()
+
+	tokenType := token.TokenType(token.INT)
+	if lex.ch == '.' && isDigit(lex.peekChar()) {
+		lex.readChar()
+		lex.readDigits()
+		tokenType = token.ILLEGAL
+	}
+	if lex.ch == 'd' && !isLetter(lex.peekChar()) && !isDigit(lex.peekChar()) {
+		lex.readChar()
+		tokenType = token.DECIMAL
+	}
+
+	return token.Token{Type: tokenType, Literal: lex.input[initialPosition:lex.position]}
+}
+
+func (lex *Lexer) readDigits() {
 	for isDigit(lex.ch) {
 		lex.readChar()
 	}
+}
+
+// Reads a double-quoted string and reports whether it interpolates anything. The code
+// between ${ and the matching } is skipped as a whole, so it can hold braces and
+// strings of its own. A backslash escapes the character after it, so \" and \${ are
+// taken literally; the escapes are left in the returned text.
+func (lex *Lexer) readString() (string, bool) {
+	initialPosition := lex.position + 1
+	template := false
+	for {
+		lex.readChar()
+		if lex.ch == '"' || lex.ch == 0 {
+			break
+		}
+		if lex.ch == '\\' && lex.peekChar() != 0 {
+			lex.readChar()
+			continue
+		}
+		if lex.ch == '$' && lex.peekChar() == '{' {
+			template = true
+			lex.readChar()
+			lex.skipInterpolation()
+			if lex.ch == 0 {
+				break
+			}
+		}
+	}
+	return lex.input[initialPosition:lex.position], template
+}
+
+// Advances from the { opening an interpolation to the } that closes it, or to EOF
+func (lex *Lexer) skipInterpolation() {
+	depth := 1
+	for depth > 0 {
+		lex.readChar()
+		switch lex.ch {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '"':
+			lex.readString()
+		case '`':
+			lex.readRawString()
+		}
+		if lex.ch == 0 {
+			return
+		}
+	}
+}
+
+// Reads a backquoted string, which is taken as is: it can span lines and nothing in
+// it is interpolated
+func (lex *Lexer) readRawString() string {
+	initialPosition := lex.position + 1
+	for {
+		lex.readChar()
+		if lex.ch == '`' || lex.ch == 0 {
+			break
+		}
+	}
 	return lex.input[initialPosition:lex.position]
 }
 
+// A piece of the literal of a TEMPLATE token: either text, or the code of an
+// interpolation without the ${ and }
+type TemplatePart struct {
+	Text string
+	Code bool
+}
+
+// Splits the literal of a TEMPLATE token into text and interpolated code. It reports
+// false if an interpolation is not closed.
+func SplitTemplate(literal string) ([]TemplatePart, bool) {
+	parts := []TemplatePart{}
+
+	lex := New(literal)
+	start := 0
+	for lex.ch != 0 {
+		if lex.ch == '\\' && lex.peekChar() != 0 {
+			lex.readChar()
+		} else if lex.ch == '$' && lex.peekChar() == '{' {
+			parts = append(parts, TemplatePart{Text: unescape(literal[start:lex.position])})
+
+			lex.readChar()
+			codeStart := lex.position + 1
+			lex.skipInterpolation()
+			if lex.ch != '}' {
+				return nil, false
+			}
+
+			parts = append(parts, TemplatePart{Text: literal[codeStart:lex.position], Code: true})
+			start = lex.position + 1
+		}
+		lex.readChar()
+	}
+	parts = append(parts, TemplatePart{Text: unescape(literal[start:])})
+
+	return parts, true
+}
+
+// Resolves the escapes \", \\ and \$ of a double-quoted string. Any other backslash is
+// kept as written.
+func unescape(text string) string {
+	if !strings.Contains(text, "\\") {
+		return text
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) && strings.IndexByte(`"\$`, text[i+1]) >= 0 {
+			i++
+		}
+		out.WriteByte(text[i])
+	}
+	return out.String()
+}
+
 // Advances through the text until EOF or the next non-whitespace character is found
 func (lex *Lexer) skipWhiteSpace() {
 	for lex.ch == ' ' || lex.ch == '\t' || lex.ch == '\n' || lex.ch == '\r' {
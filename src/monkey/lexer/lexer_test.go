@@ -26,7 +26,25 @@ func TestNextToken(t *testing.T) {
 
 	10 == 10;
 	10 != 9;
-	`
+	"foobar"
+	"foo bar"
+	12.50d;
+	7d;
+	1.5;
+	while (true) {}
+	[1, 2];
+	{"foo": "bar"}
+	s.upper()
+	struct Point { x }
+	class
+	enum match -> ... .
+	|> >> > |
+	1..=10 ..5 for x in yield
+	try catch finally throw
+	f()? a?.b c?[ ?? x ? y
+	switch case default
+	"a ${b["}"]} c"
+	` + "`raw ${x}\n\"q\"`"
 
 	tests := []struct {
 		expectedType    token.TokenType
@@ -104,6 +122,82 @@ func TestNextToken(t *testing.T) {
 		{token.NOT_EQ, "!="},
 		{token.INT, "9"},
 		{token.SEMICOLON, ";"},
+		{token.STRING, "foobar"},
+		{token.STRING, "foo bar"},
+		{token.DECIMAL, "12.50d"},
+		{token.SEMICOLON, ";"},
+		{token.DECIMAL, "7d"},
+		{token.SEMICOLON, ";"},
+		{token.ILLEGAL, "1.5"},
+		{token.SEMICOLON, ";"},
+		{token.WHILE, "while"},
+		{token.LPAREN, "("},
+		{token.TRUE, "true"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.RBRACE, "}"},
+		{token.LBRACKET, "["},
+		{token.INT, "1"},
+		{token.COMMA, ","},
+		{token.INT, "2"},
+		{token.RBRACKET, "]"},
+		{token.SEMICOLON, ";"},
+		{token.LBRACE, "{"},
+		{token.STRING, "foo"},
+		{token.COLON, ":"},
+		{token.STRING, "bar"},
+		{token.RBRACE, "}"},
+		{token.IDENT, "s"},
+		{token.DOT, "."},
+		{token.IDENT, "upper"},
+		{token.LPAREN, "("},
+		{token.RPAREN, ")"},
+		{token.STRUCT, "struct"},
+		{token.IDENT, "Point"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "x"},
+		{token.RBRACE, "}"},
+		{token.CLASS, "class"},
+		{token.ENUM, "enum"},
+		{token.MATCH, "match"},
+		{token.ARROW, "->"},
+		{token.ELLIPSIS, "..."},
+		{token.DOT, "."},
+		{token.PIPE, "|>"},
+		{token.COMPOSE, ">>"},
+		{token.GT, ">"},
+		{token.ILLEGAL, "|"},
+		{token.INT, "1"},
+		{token.RANGE_INCLUSIVE, "..="},
+		{token.INT, "10"},
+		{token.RANGE, ".."},
+		{token.INT, "5"},
+		{token.FOR, "for"},
+		{token.IDENT, "x"},
+		{token.IN, "in"},
+		{token.YIELD, "yield"},
+		{token.TRY, "try"},
+		{token.CATCH, "catch"},
+		{token.FINALLY, "finally"},
+		{token.THROW, "throw"},
+		{token.IDENT, "f"},
+		{token.LPAREN, "("},
+		{token.RPAREN, ")"},
+		{token.QUESTION, "?"},
+		{token.IDENT, "a"},
+		{token.OPTIONAL_DOT, "?."},
+		{token.IDENT, "b"},
+		{token.IDENT, "c"},
+		{token.OPTIONAL_INDEX, "?["},
+		{token.COALESCE, "??"},
+		{token.IDENT, "x"},
+		{token.TERNARY, "?"},
+		{token.IDENT, "y"},
+		{token.SWITCH, "switch"},
+		{token.CASE, "case"},
+		{token.DEFAULT, "default"},
+		{token.TEMPLATE, `a ${b["}"]} c`},
+		{token.STRING, "raw ${x}\n\"q\""},
 		{token.EOF, ""},
 	}
 
@@ -123,3 +217,41 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitTemplate(t *testing.T) {
+	parts, ok := SplitTemplate(`${a} and ${ {"k": "}"}["k"] } end`)
+	if !ok {
+		t.Fatalf("template not split")
+	}
+
+	expected := []TemplatePart{
+		{Text: ""},
+		{Text: "a", Code: true},
+		{Text: " and "},
+		{Text: ` {"k": "}"}["k"] `, Code: true},
+		{Text: " end"},
+	}
+	if len(parts) != len(expected) {
+		t.Fatalf("wrong number of parts. expected=%d, got=%d", len(expected), len(parts))
+	}
+	for i, part := range parts {
+		if part != expected[i] {
+			t.Errorf("part %d wrong. expected=%+v, got=%+v", i, expected[i], part)
+		}
+	}
+
+	parts, _ = SplitTemplate(`\${a} \"${b}\"`)
+	escaped := []TemplatePart{{Text: `${a} "`}, {Text: "b", Code: true}, {Text: `"`}}
+	if len(parts) != len(escaped) {
+		t.Fatalf("wrong number of parts with escapes. expected=%d, got=%d", len(escaped), len(parts))
+	}
+	for i, part := range parts {
+		if part != escaped[i] {
+			t.Errorf("part %d wrong. expected=%+v, got=%+v", i, escaped[i], part)
+		}
+	}
+
+	if _, ok := SplitTemplate(`a ${b`); ok {
+		t.Errorf("expected an unterminated interpolation to be reported")
+	}
+}
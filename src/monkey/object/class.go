@@ -0,0 +1,92 @@
+package object
+
+import (
+	"bytes"
+	"strings"
+)
+
+// ===================
+// Class
+// ===================
+
+// A Class is the value bound to a class declaration. Calling it creates an
+// instance and runs the init method, if the class or one of its ancestors has one.
+type Class struct {
+	Name    string
+	Parent  *Class
+	Methods map[string]*Function
+}
+
+// Looks a method up on the class, then on each of its ancestors in turn
+func (c *Class) Method(name string) (*Function, bool) {
+	for class := c; class != nil; class = class.Parent {
+		if method, ok := class.Methods[name]; ok {
+			return method, true
+		}
+	}
+	return nil, false
+}
+
+func (c *Class) Type() ObjectType { return CLASS_OBJ }
+func (c *Class) Inspect() string {
+	if c.Parent != nil {
+		return "class " + c.Name + " < " + c.Parent.Name
+	}
+	return "class " + c.Name
+}
+
+// ===================
+// Instance
+// ===================
+type Instance struct {
+	Class  *Class
+	Fields map[string]Object
+	Order  []string
+}
+
+func NewInstance(class *Class) *Instance {
+	return &Instance{Class: class, Fields: make(map[string]Object)}
+}
+
+func (i *Instance) Get(name string) (Object, bool) {
+	value, ok := i.Fields[name]
+	return value, ok
+}
+
+func (i *Instance) Set(name string, value Object) {
+	if _, ok := i.Fields[name]; !ok {
+		i.Order = append(i.Order, name)
+	}
+	i.Fields[name] = value
+}
+
+func (i *Instance) Type() ObjectType { return INSTANCE_OBJ }
+func (i *Instance) Inspect() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, name := range i.Order {
+		fields = append(fields, name+": "+i.Fields[name].Inspect())
+	}
+
+	out.WriteString(i.Class.Name)
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// ===================
+// Bound Method
+// ===================
+
+// A BoundMethod is a method read from an instance. Calling it passes the
+// instance as self ahead of the caller's arguments.
+type BoundMethod struct {
+	Receiver *Instance
+	Method   *Function
+}
+
+func (bm *BoundMethod) Type() ObjectType { return BOUND_METHOD_OBJ }
+func (bm *BoundMethod) Inspect() string  { return bm.Method.Inspect() }
@@ -0,0 +1,422 @@
+package object
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+)
+
+var (
+	objectType = reflect.TypeOf((*Object)(nil)).Elem()
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+	bigIntType = reflect.TypeOf((*big.Int)(nil))
+)
+
+func NativeBool(value bool) *Boolean {
+	if value {
+		return TRUE
+	}
+	return FALSE
+}
+
+// ===================
+// Go to Object
+// ===================
+
+// Converts a Go value into an object. Supported are nil, booleans, integers,
+// *big.Int, strings, slices, arrays, maps and functions. Structs and pointers to
+// structs are wrapped in a HostObject. Objects are returned as they are.
+func FromGo(value any) (Object, error) {
+	return fromGo(reflect.ValueOf(value))
+}
+
+func fromGo(v reflect.Value) (Object, error) {
+	if !v.IsValid() {
+		return NULL, nil
+	}
+
+	if v.Type().Implements(objectType) {
+		if isNilValue(v) {
+			return NULL, nil
+		}
+		return v.Interface().(Object), nil
+	}
+
+	if v.Type() == bigIntType {
+		if v.IsNil() {
+			return NULL, nil
+		}
+		return NewInteger(new(big.Int).Set(v.Interface().(*big.Int))), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return NativeBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Integer{Value: v.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return NewInteger(new(big.Int).SetUint64(v.Uint())), nil
+	case reflect.String:
+		return &String{Value: v.String()}, nil
+	case reflect.Slice, reflect.Array:
+		elements := make([]Object, v.Len())
+		for i := range elements {
+			element, err := fromGo(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = element
+		}
+		return &Array{Elements: elements}, nil
+	case reflect.Map:
+		return fromGoMap(v)
+	case reflect.Func:
+		if v.IsNil() {
+			return NULL, nil
+		}
+		return NewGoFunction(v.Type().String(), v.Interface())
+	case reflect.Struct:
+		return newHostObject(v)
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return NULL, nil
+		}
+		if v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Struct {
+			return newHostObject(v)
+		}
+		return fromGo(v.Elem())
+	}
+
+	return nil, fmt.Errorf("cannot convert Go value of type %s", v.Type())
+}
+
+// Converts a Go map into a Hash. Go maps are unordered, so the keys are inserted in
+// sorted order to keep the result deterministic.
+func fromGoMap(v reflect.Value) (Object, error) {
+	type entry struct {
+		key   Hashable
+		value Object
+	}
+	entries := []entry{}
+
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := fromGo(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		hashKey, ok := key.(Hashable)
+		if !ok {
+			return nil, fmt.Errorf("unusable as hash key: %s", key.Type())
+		}
+		value, err := fromGo(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{hashKey, value})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key.(Object).Inspect() < entries[j].key.(Object).Inspect()
+	})
+
+	hash := NewHash()
+	for _, e := range entries {
+		hash.Set(e.key, e.value)
+	}
+	return hash, nil
+}
+
+// Returns an Integer if value fits into an int64 and a BigInt otherwise
+func NewInteger(value *big.Int) Object {
+	if value.IsInt64() {
+		return &Integer{Value: value.Int64()}
+	}
+	return &BigInt{Value: value}
+}
+
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func:
+		return v.IsNil()
+	}
+	return false
+}
+
+// ===================
+// Object to Go
+// ===================
+
+// Converts obj into its natural Go representation: int64 or *big.Int for integers,
+// bool, string, nil for null, []any for arrays and map[string]any for hashes with
+// only string keys, map[any]any for other hashes. Objects without a Go equivalent,
+// such as functions and decimals, are returned as they are.
+func ToGo(obj Object) (any, error) {
+	v, err := ToGoValue(obj, reflect.TypeOf((*any)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// Converts obj into a Go value of type target, e.g. the type of a function parameter
+func ToGoValue(obj Object, target reflect.Type) (reflect.Value, error) {
+	if target.Kind() == reflect.Interface && target.NumMethod() == 0 {
+		natural, err := toNaturalGo(obj)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if natural == nil {
+			return reflect.Zero(target), nil
+		}
+		return reflect.ValueOf(natural), nil
+	}
+
+	if reflect.TypeOf(obj).AssignableTo(target) {
+		return reflect.ValueOf(obj), nil
+	}
+
+	if host, ok := obj.(*HostObject); ok {
+		switch {
+		case host.Value.Type().AssignableTo(target):
+			return host.Value, nil
+		case host.Value.Elem().Type().AssignableTo(target):
+			return host.Value.Elem(), nil
+		}
+	}
+
+	if obj == NULL {
+		switch target.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func:
+			return reflect.Zero(target), nil
+		}
+	}
+
+	if target == bigIntType {
+		switch obj := obj.(type) {
+		case *Integer:
+			return reflect.ValueOf(big.NewInt(obj.Value)), nil
+		case *BigInt:
+			return reflect.ValueOf(new(big.Int).Set(obj.Value)), nil
+		}
+	}
+
+	switch target.Kind() {
+	case reflect.Bool:
+		if b, ok := obj.(*Boolean); ok {
+			return reflect.ValueOf(b.Value).Convert(target), nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := obj.(*Integer); ok {
+			v := reflect.New(target).Elem()
+			if v.OverflowInt(i.Value) {
+				return reflect.Value{}, fmt.Errorf("%d overflows %s", i.Value, target)
+			}
+			v.SetInt(i.Value)
+			return v, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value := toBig(obj)
+		if value != nil {
+			v := reflect.New(target).Elem()
+			if value.Sign() < 0 || !value.IsUint64() || v.OverflowUint(value.Uint64()) {
+				return reflect.Value{}, fmt.Errorf("%s overflows %s", value, target)
+			}
+			v.SetUint(value.Uint64())
+			return v, nil
+		}
+	case reflect.String:
+		if s, ok := obj.(*String); ok {
+			return reflect.ValueOf(s.Value).Convert(target), nil
+		}
+	case reflect.Slice:
+		if arr, ok := obj.(*Array); ok {
+			v := reflect.MakeSlice(target, len(arr.Elements), len(arr.Elements))
+			for i, element := range arr.Elements {
+				converted, err := ToGoValue(element, target.Elem())
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				v.Index(i).Set(converted)
+			}
+			return v, nil
+		}
+	case reflect.Map:
+		if hash, ok := obj.(*Hash); ok {
+			v := reflect.MakeMapWithSize(target, len(hash.Pairs))
+			for _, pair := range hash.Entries() {
+				key, err := ToGoValue(pair.Key, target.Key())
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				value, err := ToGoValue(pair.Value, target.Elem())
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				v.SetMapIndex(key, value)
+			}
+			return v, nil
+		}
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot convert %s to %s", obj.Type(), target)
+}
+
+func toNaturalGo(obj Object) (any, error) {
+	switch obj := obj.(type) {
+	case *Integer:
+		return obj.Value, nil
+	case *BigInt:
+		return new(big.Int).Set(obj.Value), nil
+	case *Boolean:
+		return obj.Value, nil
+	case *String:
+		return obj.Value, nil
+	case *Null:
+		return nil, nil
+	case *Array:
+		elements := make([]any, len(obj.Elements))
+		for i, element := range obj.Elements {
+			converted, err := toNaturalGo(element)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = converted
+		}
+		return elements, nil
+	case *Hash:
+		return hashToNaturalGo(obj)
+	default:
+		return obj, nil
+	}
+}
+
+func hashToNaturalGo(hash *Hash) (any, error) {
+	stringKeys := true
+	for _, pair := range hash.Pairs {
+		if pair.Key.Type() != STRING_OBJ {
+			stringKeys = false
+			break
+		}
+	}
+
+	var target reflect.Type
+	if stringKeys {
+		target = reflect.TypeOf(map[string]any{})
+	} else {
+		target = reflect.TypeOf(map[any]any{})
+	}
+
+	v, err := ToGoValue(hash, target)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+func toBig(obj Object) *big.Int {
+	switch obj := obj.(type) {
+	case *Integer:
+		return big.NewInt(obj.Value)
+	case *BigInt:
+		return obj.Value
+	}
+	return nil
+}
+
+// ===================
+// Go Functions
+// ===================
+
+// Wraps a Go function so programs can call it. Arguments are converted with
+// ToGoValue and the result with FromGo. A function may return nothing, a value,
+// an error, or a value followed by an error. A non-nil error is returned to the
+// program as an Error object.
+func NewGoFunction(name string, fn any) (*Builtin, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return nil, fmt.Errorf("%s is not a function but %T", name, fn)
+	}
+
+	fnType := v.Type()
+	returnsError := fnType.NumOut() > 0 && fnType.Out(fnType.NumOut()-1) == errorType
+	if fnType.NumOut() > 2 || (fnType.NumOut() == 2 && !returnsError) {
+		return nil, fmt.Errorf("%s must return at most a value and an error", name)
+	}
+
+	return &Builtin{Fn: func(args ...Object) Object {
+		in, err := goArguments(name, fnType, args)
+		if err != nil {
+			return &Error{Message: err.Error(), Cause: err}
+		}
+		results, err := callGo(name, v, in)
+		if err != nil {
+			return &Error{Message: err.Error(), Cause: err}
+		}
+		return fromGoResults(name, results, returnsError)
+	}}, nil
+}
+
+// Calls fn, turning a panic into an error so a faulty Go function cannot bring down
+// the program embedding the interpreter
+func callGo(name string, fn reflect.Value, in []reflect.Value) (results []reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if cause, ok := r.(error); ok {
+				err = fmt.Errorf("panic in `%s`: %w", name, cause)
+			} else {
+				err = fmt.Errorf("panic in `%s`: %v", name, r)
+			}
+		}
+	}()
+	return fn.Call(in), nil
+}
+
+func goArguments(name string, fnType reflect.Type, args []Object) ([]reflect.Value, error) {
+	fixed := fnType.NumIn()
+	if fnType.IsVariadic() {
+		fixed--
+	}
+	if len(args) < fixed || (!fnType.IsVariadic() && len(args) > fixed) {
+		return nil, fmt.Errorf("wrong number of arguments to `%s`. got=%d, want=%d", name, len(args), fixed)
+	}
+
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		var paramType reflect.Type
+		if i >= fixed {
+			paramType = fnType.In(fixed).Elem()
+		} else {
+			paramType = fnType.In(i)
+		}
+
+		converted, err := ToGoValue(arg, paramType)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d to `%s`: %w", i+1, name, err)
+		}
+		in[i] = converted
+	}
+	return in, nil
+}
+
+func fromGoResults(name string, results []reflect.Value, returnsError bool) Object {
+	if returnsError {
+		errValue := results[len(results)-1]
+		if !errValue.IsNil() {
+			err := errValue.Interface().(error)
+			return &Error{Message: fmt.Sprintf("%s: %s", name, err), Cause: err}
+		}
+		results = results[:len(results)-1]
+	}
+
+	if len(results) == 0 {
+		return NULL
+	}
+
+	obj, err := fromGo(results[0])
+	if err != nil {
+		return &Error{Message: fmt.Sprintf("result of `%s`: %s", name, err), Cause: err}
+	}
+	return obj
+}
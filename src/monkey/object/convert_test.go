@@ -0,0 +1,121 @@
+package object
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestFromGo(t *testing.T) {
+	tests := []struct {
+		input    any
+		expected string
+	}{
+		{nil, "null"},
+		{true, "true"},
+		{42, "42"},
+		{int8(-3), "-3"},
+		{uint64(18446744073709551615), "18446744073709551615"},
+		{big.NewInt(7), "7"},
+		{"monkey", "monkey"},
+		{[]int{1, 2, 3}, "[1, 2, 3]"},
+		{[2]string{"a", "b"}, "[a, b]"},
+		{map[string]int{"b": 2, "a": 1}, "{a: 1, b: 2}"},
+		{map[int][]bool{1: {true}}, "{1: [true]}"},
+		{(*int)(nil), "null"},
+		{&Integer{Value: 5}, "5"},
+	}
+
+	for _, tt := range tests {
+		obj, err := FromGo(tt.input)
+		if err != nil {
+			t.Errorf("FromGo(%#v) failed: %s", tt.input, err)
+			continue
+		}
+		if obj.Inspect() != tt.expected {
+			t.Errorf("FromGo(%#v) wrong. got=%s, want=%s", tt.input, obj.Inspect(), tt.expected)
+		}
+	}
+
+	if _, err := FromGo(1.5); err == nil {
+		t.Errorf("expected an error converting a float")
+	}
+
+	obj, err := FromGo(struct{ Name string }{"monkey"})
+	if _, ok := obj.(*HostObject); !ok || err != nil {
+		t.Errorf("expected struct to be wrapped in a HostObject. got=%T, err=%v", obj, err)
+	}
+}
+
+func TestToGoValue(t *testing.T) {
+	hash := NewHash()
+	hash.Set(&String{Value: "a"}, &Array{Elements: []Object{&Integer{Value: 1}}})
+
+	tests := []struct {
+		input    Object
+		target   any
+		expected any
+	}{
+		{&Integer{Value: 5}, int32(0), int32(5)},
+		{&Integer{Value: 5}, uint(0), uint(5)},
+		{&BigInt{Value: new(big.Int).SetUint64(1 << 63)}, uint64(0), uint64(1 << 63)},
+		{&Integer{Value: 5}, (*big.Int)(nil), big.NewInt(5)},
+		{TRUE, false, true},
+		{&String{Value: "x"}, "", "x"},
+		{hash, map[string][]int{}, map[string][]int{"a": {1}}},
+		{NULL, []int(nil), []int(nil)},
+	}
+
+	for _, tt := range tests {
+		v, err := ToGoValue(tt.input, reflect.TypeOf(tt.target))
+		if err != nil {
+			t.Errorf("ToGoValue(%s) failed: %s", tt.input.Inspect(), err)
+			continue
+		}
+		if !reflect.DeepEqual(v.Interface(), tt.expected) {
+			t.Errorf("ToGoValue(%s) wrong. got=%#v, want=%#v", tt.input.Inspect(), v.Interface(), tt.expected)
+		}
+	}
+
+	errors := []struct {
+		input  Object
+		target any
+	}{
+		{&Integer{Value: 300}, int8(0)},
+		{&Integer{Value: -1}, uint(0)},
+		{&String{Value: "x"}, 0},
+		{&Array{Elements: []Object{TRUE}}, []int{}},
+	}
+
+	for _, tt := range errors {
+		if _, err := ToGoValue(tt.input, reflect.TypeOf(tt.target)); err == nil {
+			t.Errorf("expected an error converting %s to %T", tt.input.Inspect(), tt.target)
+		}
+	}
+}
+
+func TestToGo(t *testing.T) {
+	mixed := NewHash()
+	mixed.Set(&Integer{Value: 1}, TRUE)
+
+	tests := []struct {
+		input    Object
+		expected any
+	}{
+		{&Integer{Value: 5}, int64(5)},
+		{NULL, nil},
+		{&Array{Elements: []Object{&String{Value: "a"}, NULL}}, []any{"a", nil}},
+		{mixed, map[any]any{int64(1): true}},
+	}
+
+	for _, tt := range tests {
+		v, err := ToGo(tt.input)
+		if err != nil {
+			t.Errorf("ToGo(%s) failed: %s", tt.input.Inspect(), err)
+			continue
+		}
+		if !reflect.DeepEqual(v, tt.expected) {
+			t.Errorf("ToGo(%s) wrong. got=%#v, want=%#v", tt.input.Inspect(), v, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package object
+
+import (
+	"math/big"
+	"strings"
+)
+
+// ===================
+// Decimal
+// ===================
+
+// An exact decimal number, stored as an unscaled integer and the number of digits
+// after the decimal point. 12.50 is represented as Value=1250, Scale=2.
+type Decimal struct {
+	Value *big.Int
+	Scale int
+}
+
+func NewDecimal(value *big.Int, scale int) *Decimal {
+	return &Decimal{Value: value, Scale: scale}
+}
+
+func (d *Decimal) Type() ObjectType { return DECIMAL_OBJ }
+func (d *Decimal) Inspect() string {
+	digits := new(big.Int).Abs(d.Value).String()
+	if len(digits) <= d.Scale {
+		digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+	}
+
+	var out strings.Builder
+	if d.Value.Sign() < 0 {
+		out.WriteString("-")
+	}
+	point := len(digits) - d.Scale
+	out.WriteString(digits[:point])
+	if d.Scale > 0 {
+		out.WriteString(".")
+		out.WriteString(digits[point:])
+	}
+	return out.String()
+}
+
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota // round to nearest, ties to the even neighbour
+	RoundHalfUp                       // round to nearest, ties away from zero
+	RoundDown                         // truncate towards zero
+)
+
+var roundingModes = map[string]RoundingMode{
+	"half_even": RoundHalfEven,
+	"half_up":   RoundHalfUp,
+	"down":      RoundDown,
+}
+
+func LookupRoundingMode(name string) (RoundingMode, bool) {
+	mode, ok := roundingModes[name]
+	return mode, ok
+}
+
+// Returns a copy of d with exactly scale digits after the decimal point, rounding
+// with mode if digits have to be dropped.
+func (d *Decimal) Rescale(scale int, mode RoundingMode) *Decimal {
+	if scale >= d.Scale {
+		value := new(big.Int).Mul(d.Value, pow10(scale-d.Scale))
+		return NewDecimal(value, scale)
+	}
+	return NewDecimal(roundQuotient(d.Value, pow10(d.Scale-scale), mode), scale)
+}
+
+// Removes trailing zeros from the fractional part without going below minScale.
+func (d *Decimal) Trim(minScale int) *Decimal {
+	value := new(big.Int).Set(d.Value)
+	scale := d.Scale
+	ten := big.NewInt(10)
+	quotient, remainder := new(big.Int), new(big.Int)
+
+	for scale > minScale {
+		quotient.QuoRem(value, ten, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+		value.Set(quotient)
+		scale--
+	}
+	return NewDecimal(value, scale)
+}
+
+func (d *Decimal) Add(other *Decimal) *Decimal {
+	left, right := alignScales(d, other)
+	return NewDecimal(new(big.Int).Add(left.Value, right.Value), left.Scale)
+}
+
+func (d *Decimal) Sub(other *Decimal) *Decimal {
+	left, right := alignScales(d, other)
+	return NewDecimal(new(big.Int).Sub(left.Value, right.Value), left.Scale)
+}
+
+func (d *Decimal) Mul(other *Decimal) *Decimal {
+	return NewDecimal(new(big.Int).Mul(d.Value, other.Value), d.Scale+other.Scale)
+}
+
+// Divides d by other, producing a result with scale digits after the decimal point.
+// other must not be zero.
+func (d *Decimal) Quo(other *Decimal, scale int, mode RoundingMode) *Decimal {
+	// d / other = (d.Value * 10^other.Scale) / (other.Value * 10^d.Scale)
+	numerator := new(big.Int).Mul(d.Value, pow10(other.Scale+scale))
+	denominator := new(big.Int).Mul(other.Value, pow10(d.Scale))
+	return NewDecimal(roundQuotient(numerator, denominator, mode), scale)
+}
+
+func (d *Decimal) Neg() *Decimal {
+	return NewDecimal(new(big.Int).Neg(d.Value), d.Scale)
+}
+
+func (d *Decimal) Cmp(other *Decimal) int {
+	left, right := alignScales(d, other)
+	return left.Value.Cmp(right.Value)
+}
+
+func (d *Decimal) Sign() int { return d.Value.Sign() }
+
+func alignScales(left *Decimal, right *Decimal) (*Decimal, *Decimal) {
+	scale := max(left.Scale, right.Scale)
+	return left.Rescale(scale, RoundDown), right.Rescale(scale, RoundDown)
+}
+
+// Divides numerator by denominator and rounds the integer result using mode.
+func roundQuotient(numerator *big.Int, denominator *big.Int, mode RoundingMode) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() == 0 || mode == RoundDown {
+		return quotient
+	}
+
+	// Compare twice the remainder with the denominator to find out which half we are in
+	half := new(big.Int).Abs(remainder)
+	half.Lsh(half, 1)
+	cmp := half.Cmp(new(big.Int).Abs(denominator))
+
+	roundAway := false
+	switch mode {
+	case RoundHalfUp:
+		roundAway = cmp >= 0
+	case RoundHalfEven:
+		roundAway = cmp > 0 || (cmp == 0 && quotient.Bit(0) == 1)
+	}
+
+	if roundAway {
+		if numerator.Sign()*denominator.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,95 @@
+package object
+
+import (
+	"bytes"
+	"strings"
+)
+
+// ===================
+// Enum
+// ===================
+
+// An Enum is the value bound to an enum declaration. Its variants are reached
+// as members, such as Shape.Circle.
+type Enum struct {
+	Name     string
+	Variants []*EnumVariant
+}
+
+func (e *Enum) Variant(name string) (*EnumVariant, bool) {
+	for _, variant := range e.Variants {
+		if variant.Name == name {
+			return variant, true
+		}
+	}
+	return nil, false
+}
+
+func (e *Enum) Type() ObjectType { return ENUM_OBJ }
+func (e *Enum) Inspect() string {
+	variants := []string{}
+	for _, variant := range e.Variants {
+		variants = append(variants, variant.Inspect())
+	}
+	return "enum " + e.Name + " { " + strings.Join(variants, ", ") + " }"
+}
+
+// ===================
+// Enum Variant
+// ===================
+
+// An EnumVariant constructs tagged values of one variant. Calling it takes one
+// argument per field; a variant without fields is itself the only value it has.
+type EnumVariant struct {
+	Enum   *Enum
+	Name   string
+	Fields []string
+	Unit   *EnumValue // the single value of a variant without fields
+}
+
+func (ev *EnumVariant) Type() ObjectType { return ENUM_VARIANT_OBJ }
+func (ev *EnumVariant) Inspect() string {
+	if len(ev.Fields) == 0 {
+		return ev.Name
+	}
+	return ev.Name + "(" + strings.Join(ev.Fields, ", ") + ")"
+}
+
+// ===================
+// Enum Value
+// ===================
+type EnumValue struct {
+	Variant *EnumVariant
+	Values  []Object
+}
+
+func (ev *EnumValue) Get(name string) (Object, bool) {
+	for i, field := range ev.Variant.Fields {
+		if field == name {
+			return ev.Values[i], true
+		}
+	}
+	return nil, false
+}
+
+func (ev *EnumValue) Type() ObjectType { return ENUM_VALUE_OBJ }
+func (ev *EnumValue) Inspect() string {
+	var out bytes.Buffer
+
+	out.WriteString(ev.Variant.Enum.Name)
+	out.WriteString(".")
+	out.WriteString(ev.Variant.Name)
+
+	if len(ev.Values) > 0 {
+		values := []string{}
+		for _, value := range ev.Values {
+			values = append(values, value.Inspect())
+		}
+
+		out.WriteString("(")
+		out.WriteString(strings.Join(values, ", "))
+		out.WriteString(")")
+	}
+
+	return out.String()
+}
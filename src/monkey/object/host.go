@@ -0,0 +1,73 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ===================
+// Host Object
+// ===================
+
+// A HostObject exposes a Go struct to programs. Its exported fields can be read and
+// its exported methods called, with arguments and results converted like those of
+// Go functions.
+type HostObject struct {
+	Value reflect.Value
+}
+
+// Wraps a struct or a pointer to one. Structs passed by value are copied, so
+// programs never observe later changes to the original.
+func NewHostObject(value any) (*HostObject, error) {
+	return newHostObject(reflect.ValueOf(value))
+}
+
+func newHostObject(v reflect.Value) (*HostObject, error) {
+	switch {
+	case v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Struct:
+		return &HostObject{Value: v}, nil
+	case v.Kind() == reflect.Struct:
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		return &HostObject{Value: ptr}, nil
+	default:
+		return nil, fmt.Errorf("cannot wrap Go value of type %s", v.Type())
+	}
+}
+
+func (h *HostObject) Type() ObjectType { return HOST_OBJ }
+func (h *HostObject) Inspect() string {
+	return fmt.Sprintf("%s%+v", h.Value.Elem().Type(), h.Value.Elem().Interface())
+}
+
+// Returns the value of the exported field called name, or the method called name
+// bound to the wrapped value.
+func (h *HostObject) GetMember(name string) (Object, error) {
+	// Only exported methods are visible through reflection
+	if method := h.Value.MethodByName(name); method.IsValid() {
+		return NewGoFunction(h.typeName()+"."+name, method.Interface())
+	}
+
+	field, ok := h.Value.Elem().Type().FieldByName(name)
+	if !ok {
+		return nil, fmt.Errorf("%s has no field or method %s", h.typeName(), name)
+	}
+	if !field.IsExported() {
+		return nil, fmt.Errorf("cannot access unexported field %s of %s", name, h.typeName())
+	}
+
+	// A promoted field can sit behind an embedded pointer that is nil
+	value, err := h.Value.Elem().FieldByIndexErr(field.Index)
+	if err != nil {
+		return nil, fmt.Errorf("field %s of %s: %w", name, h.typeName(), err)
+	}
+	obj, err := fromGo(value)
+	if err != nil {
+		return nil, fmt.Errorf("field %s of %s: %w", name, h.typeName(), err)
+	}
+	return obj, nil
+}
+
+func (h *HostObject) typeName() string {
+	return h.Value.Elem().Type().String()
+}
@@ -1,15 +1,49 @@
 package object
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"monkey/ast"
+	"strings"
+)
 
 type ObjectType string
 
 const (
 	INTEGER_OBJ      = "INTEGER"
+	BIGINT_OBJ       = "BIGINT"
+	DECIMAL_OBJ      = "DECIMAL"
+	STRING_OBJ       = "STRING"
 	BOOLEAN_OBJ      = "BOOLEAN"
 	NULL_OBJ         = "NULL"
 	ERROR_OBJ        = "ERROR"
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
+	FUNCTION_OBJ     = "FUNCTION"
+	BUILTIN_OBJ      = "BUILTIN"
+	ARRAY_OBJ        = "ARRAY"
+	HASH_OBJ         = "HASH"
+	HOST_OBJ         = "HOST"
+	STRUCT_TYPE_OBJ  = "STRUCT_TYPE"
+	STRUCT_OBJ       = "STRUCT"
+	CLASS_OBJ        = "CLASS"
+	INSTANCE_OBJ     = "INSTANCE"
+	BOUND_METHOD_OBJ = "BOUND_METHOD"
+	ENUM_OBJ         = "ENUM"
+	ENUM_VARIANT_OBJ = "ENUM_VARIANT"
+	ENUM_VALUE_OBJ   = "ENUM_VALUE"
+	RANGE_OBJ        = "RANGE"
+	SEQUENCE_OBJ     = "SEQUENCE"
+	GENERATOR_OBJ    = "GENERATOR"
+	EXCEPTION_OBJ    = "EXCEPTION"
+)
+
+// Booleans and null are immutable, so every interpreter shares the same instances
+var (
+	NULL  = &Null{}
+	TRUE  = &Boolean{Value: true}
+	FALSE = &Boolean{Value: false}
 )
 
 type Object interface {
@@ -27,6 +61,16 @@ type Integer struct {
 func (i *Integer) Inspect() string  { return fmt.Sprintf("%d", i.Value) }
 func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
 
+// ===================
+// BigInt
+// ===================
+type BigInt struct {
+	Value *big.Int
+}
+
+func (bi *BigInt) Inspect() string  { return bi.Value.String() }
+func (bi *BigInt) Type() ObjectType { return BIGINT_OBJ }
+
 // ===================
 // Boolean
 // ===================
@@ -37,6 +81,16 @@ type Boolean struct {
 func (i *Boolean) Inspect() string  { return fmt.Sprintf("%t", i.Value) }
 func (i *Boolean) Type() ObjectType { return BOOLEAN_OBJ }
 
+// ===================
+// String
+// ===================
+type String struct {
+	Value string
+}
+
+func (s *String) Inspect() string  { return s.Value }
+func (s *String) Type() ObjectType { return STRING_OBJ }
+
 // ===================
 // Null
 // ===================
@@ -60,7 +114,210 @@ func (rv *ReturnValue) Type() ObjectType { return RETURN_VALUE_OBJ }
 // ===================
 type Error struct {
 	Message string
+	// The kind of error a script sees when it catches it, such as TypeError. Left
+	// empty for most runtime errors, which are caught as a RuntimeError.
+	Kind string
+	// The functions the error has propagated out of so far, innermost first
+	Stack []string
+	// Set when the error is really an early return made by the ? operator. It travels
+	// up like an error, so that every expression it passes through stops, until it
+	// reaches the function it returns from.
+	Return Object
+	// The Go error that caused evaluation to stop, if any. Hosts can inspect it with
+	// errors.Is, e.g. to tell a timeout apart from an error in the program.
+	Cause error
 }
 
 func (e *Error) Inspect() string  { return e.Message }
-func (e *Error) Type() ObjectType { return RETURN_VALUE_OBJ }
+func (e *Error) Type() ObjectType { return ERROR_OBJ }
+
+// Errors can be returned as Go errors, e.g. to code embedding the interpreter
+func (e *Error) Error() string { return e.Message }
+func (e *Error) Unwrap() error { return e.Cause }
+
+// ===================
+// Exception
+// ===================
+
+// An Exception describes an error caught by a try expression, or one made with error()
+// to be thrown later. Unlike an *Error, it is an ordinary value that does not stop
+// evaluation.
+type Exception struct {
+	Message string
+	Kind    string
+	Stack   []string // the functions the error propagated out of, innermost first
+}
+
+func (e *Exception) Inspect() string  { return e.Kind + ": " + e.Message }
+func (e *Exception) Type() ObjectType { return EXCEPTION_OBJ }
+
+// ===================
+// Function
+// ===================
+type Function struct {
+	Name       string // the name it was declared or first bound under, used in stack traces
+	Parameters []*ast.Identifier
+	Patterns   []ast.Pattern    // destructuring pattern of each parameter, nil if none destructure
+	Defaults   []ast.Expression // default value of each parameter, nil if none have one
+	Rest       *ast.Identifier  // collects surplus arguments, nil if the function has none
+	Body       *ast.BlockStatement
+	Env        *Environment
+	Generator  bool // calling it returns a generator instead of running the body
+}
+
+func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
+func (f *Function) Inspect() string {
+	var out bytes.Buffer
+
+	out.WriteString("fn")
+	if f.Generator {
+		out.WriteString("*")
+	}
+	out.WriteString("(")
+	out.WriteString(ast.FormatParameters(f.Parameters, f.Defaults, f.Rest))
+	out.WriteString(") {\n")
+	out.WriteString(f.Body.String())
+	out.WriteString("\n}")
+
+	return out.String()
+}
+
+// ===================
+// Builtin
+// ===================
+type BuiltinFunction func(args ...Object) Object
+
+type Builtin struct {
+	Fn BuiltinFunction
+}
+
+func (b *Builtin) Inspect() string  { return "builtin function" }
+func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
+
+// ===================
+// Array
+// ===================
+type Array struct {
+	Elements []Object
+}
+
+func (ao *Array) Type() ObjectType { return ARRAY_OBJ }
+func (ao *Array) Inspect() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, e := range ao.Elements {
+		elements = append(elements, e.Inspect())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+// ===================
+// Hash
+// ===================
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+}
+
+// Objects that can be used as keys of a Hash
+type Hashable interface {
+	HashKey() HashKey
+}
+
+func (b *Boolean) HashKey() HashKey {
+	var value uint64
+
+	if b.Value {
+		value = 1
+	} else {
+		value = 0
+	}
+
+	return HashKey{Type: b.Type(), Value: value}
+}
+
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+}
+
+func (s *String) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(s.Value))
+
+	return HashKey{Type: s.Type(), Value: h.Sum64()}
+}
+
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+// A Hash remembers the order its keys were inserted in, which is the order they
+// are listed in by Inspect.
+type Hash struct {
+	Pairs map[HashKey]HashPair
+	Order []HashKey
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := h.Pairs[key.HashKey()]
+	return pair.Value, ok
+}
+
+func (h *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+	if _, ok := h.Pairs[hashKey]; !ok {
+		h.Order = append(h.Order, hashKey)
+	}
+	h.Pairs[hashKey] = HashPair{Key: key.(Object), Value: value}
+}
+
+func (h *Hash) Delete(key Hashable) {
+	hashKey := key.HashKey()
+	if _, ok := h.Pairs[hashKey]; !ok {
+		return
+	}
+
+	delete(h.Pairs, hashKey)
+	for i, k := range h.Order {
+		if k == hashKey {
+			h.Order = append(h.Order[:i:i], h.Order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Returns the pairs of the hash in insertion order
+func (h *Hash) Entries() []HashPair {
+	entries := make([]HashPair, 0, len(h.Order))
+	for _, key := range h.Order {
+		entries = append(entries, h.Pairs[key])
+	}
+	return entries
+}
+
+func (h *Hash) Type() ObjectType { return HASH_OBJ }
+func (h *Hash) Inspect() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range h.Entries() {
+		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
@@ -0,0 +1,105 @@
+package object
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ===================
+// Range
+// ===================
+
+// A Range is a lazy series of integers from Start up to End, excluding End unless
+// the range is inclusive. Either bound may be left open, as in s[..5] or 1.., in
+// which case the range slices from the beginning or to the end of whatever it
+// indexes, and a range without an end counts up forever.
+type Range struct {
+	Start     int64
+	End       int64
+	Step      int64
+	HasStart  bool
+	HasEnd    bool
+	Inclusive bool
+}
+
+// Whether i lies before the end of the range, taking the direction of the step into account
+func (r *Range) Includes(i int64) bool {
+	switch {
+	case !r.HasEnd:
+		return true
+	case r.Step > 0 && r.Inclusive:
+		return i <= r.End
+	case r.Step > 0:
+		return i < r.End
+	case r.Inclusive:
+		return i >= r.End
+	default:
+		return i > r.End
+	}
+}
+
+// Counts the integers in a range with both bounds. The count is a big.Int since a
+// range over all of int64 holds more integers than even a uint64 can count.
+func (r *Range) Len() *big.Int {
+	step := r.Step
+	first, last := r.Start, r.End
+	if step < 0 {
+		step = -step
+		first, last = last, first
+	}
+
+	// Differences are taken as unsigned so that ranges spanning most of int64 cannot overflow
+	var gaps uint64
+	switch {
+	case r.Inclusive && last >= first:
+		gaps = (uint64(last) - uint64(first)) / uint64(step)
+	case !r.Inclusive && last > first:
+		gaps = (uint64(last) - uint64(first) - 1) / uint64(step)
+	default:
+		return new(big.Int)
+	}
+	return new(big.Int).Add(new(big.Int).SetUint64(gaps), big.NewInt(1))
+}
+
+// Steps through the integers of a range with a start
+func (r *Range) Iterate() Iterator {
+	next, done := r.Start, false
+
+	return IteratorFunc(func() (Object, bool) {
+		if done || !r.Includes(next) {
+			return nil, false
+		}
+
+		value := next
+		next += r.Step
+
+		// Stepping past the largest or smallest integer ends the range
+		if (r.Step > 0 && next < value) || (r.Step < 0 && next > value) {
+			done = true
+		}
+
+		return &Integer{Value: value}, true
+	})
+}
+
+func (r *Range) Type() ObjectType { return RANGE_OBJ }
+func (r *Range) Inspect() string {
+	out := ""
+	if r.HasStart {
+		out += fmt.Sprintf("%d", r.Start)
+	}
+
+	out += ".."
+	if r.Inclusive {
+		out += "="
+	}
+
+	if r.HasEnd {
+		out += fmt.Sprintf("%d", r.End)
+	}
+	if r.Step != 1 {
+		out += fmt.Sprintf(" step %d", r.Step)
+	}
+
+	return out
+}
@@ -0,0 +1,44 @@
+package object
+
+// ===================
+// Iterator
+// ===================
+
+// An Iterator steps through the elements of a sequence. Next reports false once the
+// elements run out, and an *Error element ends the iteration early.
+type Iterator interface {
+	Next() (Object, bool)
+}
+
+type IteratorFunc func() (Object, bool)
+
+func (f IteratorFunc) Next() (Object, bool) { return f() }
+
+// ===================
+// Sequence
+// ===================
+
+// A Sequence is a lazily evaluated series of elements, such as a range passed
+// through map or filter. Nothing is computed until the sequence is iterated, and
+// each iteration starts over from the beginning.
+type Sequence struct {
+	Iterate func() Iterator
+}
+
+func (s *Sequence) Type() ObjectType { return SEQUENCE_OBJ }
+func (s *Sequence) Inspect() string  { return "sequence" }
+
+// ===================
+// Generator
+// ===================
+
+// A Generator is what calling a generator function returns. Each call to Next runs the
+// function until it yields its next element, so unlike a Sequence it can be iterated
+// only once.
+type Generator struct {
+	Resume func() (Object, bool)
+}
+
+func (g *Generator) Type() ObjectType     { return GENERATOR_OBJ }
+func (g *Generator) Inspect() string      { return "generator" }
+func (g *Generator) Next() (Object, bool) { return g.Resume() }
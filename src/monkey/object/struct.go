@@ -0,0 +1,75 @@
+package object
+
+import (
+	"bytes"
+	"strings"
+)
+
+// ===================
+// Struct Type
+// ===================
+
+// A StructType is the value bound to a struct declaration. Calling it constructs
+// an instance from positional arguments, one per field.
+type StructType struct {
+	Name   string
+	Fields []string
+}
+
+// Returns the position of the named field, or -1 if the struct has no such field
+func (st *StructType) FieldIndex(name string) int {
+	for i, field := range st.Fields {
+		if field == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (st *StructType) Type() ObjectType { return STRUCT_TYPE_OBJ }
+func (st *StructType) Inspect() string {
+	return "struct " + st.Name + " { " + strings.Join(st.Fields, ", ") + " }"
+}
+
+// ===================
+// Struct
+// ===================
+type Struct struct {
+	Definition *StructType
+	Values     []Object
+}
+
+func (s *Struct) Get(name string) (Object, bool) {
+	idx := s.Definition.FieldIndex(name)
+	if idx < 0 {
+		return nil, false
+	}
+	return s.Values[idx], true
+}
+
+// Updates an existing field, reporting false if the struct has no such field
+func (s *Struct) Set(name string, value Object) bool {
+	idx := s.Definition.FieldIndex(name)
+	if idx < 0 {
+		return false
+	}
+	s.Values[idx] = value
+	return true
+}
+
+func (s *Struct) Type() ObjectType { return STRUCT_OBJ }
+func (s *Struct) Inspect() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for i, field := range s.Definition.Fields {
+		fields = append(fields, field+": "+s.Values[i].Inspect())
+	}
+
+	out.WriteString(s.Definition.Name)
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
@@ -1,34 +1,56 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"monkey/ast"
 	"monkey/lexer"
 	"monkey/token"
 	"strconv"
+	"strings"
 )
 
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN      // x.y = z
+	TERNARY     // c ? a : b
+	PIPE        // x |> f
+	COALESCE    // a ?? b
+	COMPOSE     // f >> g
 	EQUALS      // ==
 	LESSGREATER // less than (<) or greater than (>)
+	RANGE       // 1..10
 	SUM         // +
 	PRODUCT     // *
 	PREFIX      // -X or !X
 	CALL        // myFunction(X)
+	INDEX       // array[index]
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
-	token.LPAREN:   CALL,
+	token.ASSIGN:          ASSIGN,
+	token.TERNARY:         TERNARY,
+	token.PIPE:            PIPE,
+	token.COALESCE:        COALESCE,
+	token.COMPOSE:         COMPOSE,
+	token.EQ:              EQUALS,
+	token.NOT_EQ:          EQUALS,
+	token.LT:              LESSGREATER,
+	token.GT:              LESSGREATER,
+	token.RANGE:           RANGE,
+	token.RANGE_INCLUSIVE: RANGE,
+	token.PLUS:            SUM,
+	token.MINUS:           SUM,
+	token.SLASH:           PRODUCT,
+	token.ASTERISK:        PRODUCT,
+	token.LPAREN:          CALL,
+	token.DOT:             CALL,
+	token.QUESTION:        CALL,
+	token.OPTIONAL_DOT:    CALL,
+	token.OPTIONAL_INDEX:  INDEX,
+	token.LBRACKET:        INDEX,
 }
 
 type (
@@ -45,6 +67,12 @@ type Parser struct {
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
+
+	// Whether the innermost function being parsed is a generator, where yield is allowed
+	inGenerator bool
+	// A postfix ? followed straight away by an expression, which is most likely a
+	// conditional written without a space before the ?, as in c? a : b
+	danglingQuestion *ast.PropagateExpression
 }
 
 func New(lex *lexer.Lexer) *Parser {
@@ -57,13 +85,28 @@ func New(lex *lexer.Lexer) *Parser {
 	par.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	par.registerPrefix(token.IDENT, par.parseIdentifier)
 	par.registerPrefix(token.INT, par.parseIntegerLiteral)
+	par.registerPrefix(token.DECIMAL, par.parseDecimalLiteral)
+	par.registerPrefix(token.STRING, par.parseStringLiteral)
+	par.registerPrefix(token.TEMPLATE, par.parseTemplateLiteral)
 	par.registerPrefix(token.TRUE, par.parseBoolean)
 	par.registerPrefix(token.FALSE, par.parseBoolean)
 	par.registerPrefix(token.BANG, par.parsePrefixExpression)
 	par.registerPrefix(token.MINUS, par.parsePrefixExpression)
 	par.registerPrefix(token.LPAREN, par.parseGroupedExpression)
 	par.registerPrefix(token.IF, par.parseIfExpression)
+	par.registerPrefix(token.WHILE, par.parseWhileExpression)
 	par.registerPrefix(token.FUNCTION, par.parseFunctionLiteral)
+	par.registerPrefix(token.LBRACKET, par.parseArrayLiteral)
+	par.registerPrefix(token.LBRACE, par.parseHashLiteral)
+	par.registerPrefix(token.MATCH, par.parseMatchExpression)
+	par.registerPrefix(token.SWITCH, par.parseSwitchExpression)
+	par.registerPrefix(token.ELLIPSIS, par.parseSpreadExpression)
+	par.registerPrefix(token.RANGE, par.parseOpenRangeExpression)
+	par.registerPrefix(token.RANGE_INCLUSIVE, par.parseOpenRangeExpression)
+	par.registerPrefix(token.FOR, par.parseForExpression)
+	par.registerPrefix(token.YIELD, par.parseYieldExpression)
+	par.registerPrefix(token.TRY, par.parseTryExpression)
+	par.registerPrefix(token.THROW, par.parseThrowExpression)
 
 	// Infix parsing functions
 	par.infixParseFns = make(map[token.TokenType]infixParseFn)
@@ -76,6 +119,18 @@ func New(lex *lexer.Lexer) *Parser {
 	par.registerInfix(token.LT, par.parseInfixExpression)
 	par.registerInfix(token.GT, par.parseInfixExpression)
 	par.registerInfix(token.LPAREN, par.parseCallExpression)
+	par.registerInfix(token.LBRACKET, par.parseIndexExpression)
+	par.registerInfix(token.DOT, par.parseMemberExpression)
+	par.registerInfix(token.ASSIGN, par.parseAssignExpression)
+	par.registerInfix(token.PIPE, par.parsePipeExpression)
+	par.registerInfix(token.QUESTION, par.parsePropagateExpression)
+	par.registerInfix(token.OPTIONAL_DOT, par.parseOptionalChain)
+	par.registerInfix(token.OPTIONAL_INDEX, par.parseOptionalIndexExpression)
+	par.registerInfix(token.COALESCE, par.parseCoalesceExpression)
+	par.registerInfix(token.TERNARY, par.parseConditionalExpression)
+	par.registerInfix(token.COMPOSE, par.parseInfixExpression)
+	par.registerInfix(token.RANGE, par.parseRangeExpression)
+	par.registerInfix(token.RANGE_INCLUSIVE, par.parseRangeExpression)
 
 	// Read twice to set both curToken and peekToken
 	par.advanceTokens()
@@ -108,25 +163,40 @@ func (par *Parser) advanceTokens() {
 }
 
 func (par *Parser) parseStatement() ast.Statement {
+	dangling := par.danglingQuestion
+	par.danglingQuestion = nil
+
 	switch par.curToken.Type {
 	case token.LET:
 		return par.parseLetStatement()
 	case token.RETURN:
 		return par.parseReturnStatement()
+	case token.STRUCT:
+		return par.parseStructStatement()
+	case token.CLASS:
+		return par.parseClassStatement()
+	case token.ENUM:
+		return par.parseEnumStatement()
 	default:
-		return par.parseExpressionStatement()
+		return par.parseExpressionStatement(dangling)
 	}
 }
 
 func (par *Parser) parseLetStatement() ast.Statement {
 	stmt := &ast.LetStatement{Token: par.curToken}
 
-	if !par.peekAssertAdvance(token.IDENT) {
-		return nil
+	if par.peekTokenIs(token.LBRACKET) || par.peekTokenIs(token.LBRACE) {
+		par.advanceTokens()
+		if stmt.Pattern = par.parsePattern(); stmt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !par.peekAssertAdvance(token.IDENT) {
+			return nil
+		}
+		stmt.Name = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
 	}
 
-	stmt.Name = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
-
 	if !par.peekAssertAdvance(token.ASSIGN) {
 		return nil
 	}
@@ -135,7 +205,7 @@ func (par *Parser) parseLetStatement() ast.Statement {
 
 	stmt.Value = par.parseExpression(LOWEST)
 
-	for !par.curTokenIs(token.SEMICOLON) {
+	if par.peekTokenIs(token.SEMICOLON) {
 		par.advanceTokens()
 	}
 
@@ -149,17 +219,21 @@ func (par *Parser) parseReturnStatement() ast.Statement {
 
 	stmt.ReturnValue = par.parseExpression(LOWEST)
 
-	for !par.curTokenIs(token.SEMICOLON) {
+	if par.peekTokenIs(token.SEMICOLON) {
 		par.advanceTokens()
 	}
 
 	return stmt
 }
 
-func (par *Parser) parseExpressionStatement() ast.Statement {
+// dangling is the ? the previous statement ended with, if an expression followed it
+func (par *Parser) parseExpressionStatement(dangling *ast.PropagateExpression) ast.Statement {
 	stmt := &ast.ExpressionStatement{Token: par.curToken}
 
 	stmt.Expression = par.parseExpression(LOWEST)
+	if dangling != nil && par.peekTokenIs(token.COLON) {
+		par.misspacedConditionalError()
+	}
 
 	if par.peekTokenIs(token.SEMICOLON) {
 		par.advanceTokens()
@@ -191,13 +265,27 @@ func (par *Parser) parseExpression(precedence int) ast.Expression {
 }
 
 func (par *Parser) parseIdentifier() ast.Expression {
-	return &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+	ident := &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+
+	// A capitalized name directly followed by a brace constructs a struct
+	if par.peekTokenIs(token.LBRACE) && isStructName(ident.Value) {
+		return par.parseStructLiteral(ident)
+	}
+
+	return ident
+}
+
+func isStructName(name string) bool {
+	return name[0] >= 'A' && name[0] <= 'Z'
 }
 
 func (par *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: par.curToken}
 
 	value, err := strconv.ParseInt(par.curToken.Literal, 0, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return par.parseBigIntegerLiteral()
+	}
 	if err != nil {
 		msg := fmt.Sprintf("could not parse %q as integer", par.curToken.Literal)
 		par.errors = append(par.errors, msg)
@@ -207,6 +295,80 @@ func (par *Parser) parseIntegerLiteral() ast.Expression {
 	return lit
 }
 
+func (par *Parser) parseBigIntegerLiteral() ast.Expression {
+	lit := &ast.BigIntegerLiteral{Token: par.curToken}
+
+	value, ok := new(big.Int).SetString(par.curToken.Literal, 0)
+	if !ok {
+		msg := fmt.Sprintf("could not parse %q as integer", par.curToken.Literal)
+		par.errors = append(par.errors, msg)
+	}
+
+	lit.Value = value
+	return lit
+}
+
+func (par *Parser) parseDecimalLiteral() ast.Expression {
+	lit := &ast.DecimalLiteral{Token: par.curToken}
+
+	digits := strings.TrimSuffix(par.curToken.Literal, "d")
+	whole, fraction, _ := strings.Cut(digits, ".")
+
+	value, ok := new(big.Int).SetString(whole+fraction, 10)
+	if !ok {
+		msg := fmt.Sprintf("could not parse %q as decimal", par.curToken.Literal)
+		par.errors = append(par.errors, msg)
+	}
+
+	lit.Value = value
+	lit.Scale = len(fraction)
+	return lit
+}
+
+func (par *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{Token: par.curToken, Value: par.curToken.Literal}
+}
+
+// Parses the code of each interpolation in a template with a parser of its own
+func (par *Parser) parseTemplateLiteral() ast.Expression {
+	tl := &ast.TemplateLiteral{Token: par.curToken}
+
+	parts, ok := lexer.SplitTemplate(par.curToken.Literal)
+	if !ok {
+		par.errors = append(par.errors, "unterminated ${ in string")
+		return nil
+	}
+
+	for _, part := range parts {
+		if !part.Code {
+			if part.Text != "" {
+				text := token.Token{Type: token.STRING, Literal: part.Text}
+				tl.Parts = append(tl.Parts, &ast.StringLiteral{Token: text, Value: part.Text})
+			}
+			continue
+		}
+
+		inner := New(lexer.New(part.Text))
+		if inner.curTokenIs(token.EOF) {
+			par.errors = append(par.errors, "empty ${} in string")
+			return nil
+		}
+
+		exp := inner.parseExpression(LOWEST)
+		if len(inner.errors) == 0 && !inner.peekTokenIs(token.EOF) {
+			msg := fmt.Sprintf("unexpected %s in ${%s}", inner.peekToken.Literal, part.Text)
+			inner.errors = append(inner.errors, msg)
+		}
+		if len(inner.errors) > 0 {
+			par.errors = append(par.errors, inner.errors...)
+			return nil
+		}
+		tl.Parts = append(tl.Parts, exp)
+	}
+
+	return tl
+}
+
 func (par *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: par.curToken, Value: par.curTokenIs(token.TRUE)}
 }
@@ -234,34 +396,492 @@ func (par *Parser) parseInfixExpression(leftToken ast.Expression) ast.Expression
 	return expression
 }
 
+func (par *Parser) parsePipeExpression(left ast.Expression) ast.Expression {
+	exp := &ast.PipeExpression{Token: par.curToken, Left: left}
+
+	par.advanceTokens()
+	exp.Right = par.parseExpression(PIPE)
+
+	return exp
+}
+
+func (par *Parser) parsePropagateExpression(left ast.Expression) ast.Expression {
+	exp := &ast.PropagateExpression{Token: par.curToken, Value: left}
+	if par.prefixParseFns[par.peekToken.Type] != nil {
+		par.danglingQuestion = exp
+	}
+	return exp
+}
+
+// Reports c? a : b, which reads as c? followed by the statement a and a stray colon.
+// The alternative is skipped.
+func (par *Parser) misspacedConditionalError() {
+	par.advanceTokens()
+	par.advanceTokens()
+	par.parseExpression(TERNARY - 1)
+
+	par.errors = append(par.errors, misspacedConditional)
+}
+
+// Parses a range without a start, such as the ..5 in s[..5]
+func (par *Parser) parseOpenRangeExpression() ast.Expression {
+	return par.parseRangeExpression(nil)
+}
+
+func (par *Parser) parseRangeExpression(start ast.Expression) ast.Expression {
+	exp := &ast.RangeExpression{Token: par.curToken, Start: start, Inclusive: par.curTokenIs(token.RANGE_INCLUSIVE)}
+
+	if !par.rangeEndIsOpen() {
+		par.advanceTokens()
+		exp.End = par.parseExpression(RANGE)
+	} else if exp.Inclusive {
+		par.errors = append(par.errors, "inclusive range must have an end")
+		return nil
+	}
+
+	// The step is introduced by a contextual keyword, so step remains usable as a name
+	if par.peekTokenIs(token.IDENT) && par.peekToken.Literal == "step" {
+		par.advanceTokens()
+		par.advanceTokens()
+		exp.Step = par.parseExpression(RANGE)
+	}
+
+	return exp
+}
+
+func (par *Parser) rangeEndIsOpen() bool {
+	switch par.peekToken.Type {
+	case token.RBRACKET, token.RPAREN, token.RBRACE, token.COMMA, token.SEMICOLON, token.EOF:
+		return true
+	case token.IDENT:
+		return par.peekToken.Literal == "step"
+	default:
+		return false
+	}
+}
+
+func (par *Parser) parseForExpression() ast.Expression {
+	exp := &ast.ForExpression{Token: par.curToken}
+
+	if !par.peekAssertAdvance(token.LPAREN) {
+		return nil
+	}
+
+	par.advanceTokens()
+	if exp.Pattern = par.parsePattern(); exp.Pattern == nil {
+		return nil
+	}
+
+	if !par.peekAssertAdvance(token.IN) {
+		return nil
+	}
+
+	par.advanceTokens()
+	exp.Iterable = par.parseExpression(LOWEST)
+
+	if !par.peekAssertAdvance(token.RPAREN) {
+		return nil
+	}
+
+	if !par.peekAssertAdvance(token.LBRACE) {
+		return nil
+	}
+
+	exp.Body = par.parseBlockStatement()
+
+	return exp
+}
+
+func (par *Parser) parseTryExpression() ast.Expression {
+	exp := &ast.TryExpression{Token: par.curToken}
+
+	if !par.peekAssertAdvance(token.LBRACE) {
+		return nil
+	}
+	exp.Body = par.parseBlockStatement()
+
+	if par.peekTokenIs(token.CATCH) {
+		par.advanceTokens()
+
+		if par.peekTokenIs(token.LPAREN) {
+			par.advanceTokens()
+			if !par.peekAssertAdvance(token.IDENT) {
+				return nil
+			}
+			exp.Parameter = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+			if !par.peekAssertAdvance(token.RPAREN) {
+				return nil
+			}
+		}
+
+		if !par.peekAssertAdvance(token.LBRACE) {
+			return nil
+		}
+		exp.Catch = par.parseBlockStatement()
+	}
+
+	if par.peekTokenIs(token.FINALLY) {
+		par.advanceTokens()
+		if !par.peekAssertAdvance(token.LBRACE) {
+			return nil
+		}
+		exp.Finally = par.parseBlockStatement()
+	}
+
+	if exp.Catch == nil && exp.Finally == nil {
+		par.errors = append(par.errors, "try without catch or finally")
+		return nil
+	}
+
+	return exp
+}
+
+func (par *Parser) parseThrowExpression() ast.Expression {
+	exp := &ast.ThrowExpression{Token: par.curToken}
+
+	par.advanceTokens()
+	exp.Value = par.parseExpression(LOWEST)
+
+	return exp
+}
+
 func (par *Parser) parseCallExpression(functionIdentifier ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: par.curToken, Function: functionIdentifier}
 	exp.Arguments = par.parseCallArguments()
 	return exp
 }
 
+// Parses call arguments, which are expressions optionally followed by named arguments: f(1, y: 2)
 func (par *Parser) parseCallArguments() []ast.Expression {
 	args := []ast.Expression{}
+	named := false
 
-	if par.peekTokenIs(token.RPAREN) {
+	for !par.peekTokenIs(token.RPAREN) {
 		par.advanceTokens()
+
+		if par.curTokenIs(token.IDENT) && par.peekTokenIs(token.COLON) {
+			arg := &ast.NamedArgument{Token: par.curToken, Name: &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}}
+			par.advanceTokens()
+			par.advanceTokens()
+			arg.Value = par.parseExpression(LOWEST)
+			args = append(args, arg)
+			named = true
+		} else if named {
+			par.errors = append(par.errors, "positional argument follows named argument")
+			return nil
+		} else {
+			args = append(args, par.parseExpression(LOWEST))
+		}
+
+		if !par.peekTokenIs(token.RPAREN) && !par.peekAssertAdvance(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !par.peekAssertAdvance(token.RPAREN) {
 		return nil
 	}
 
+	return args
+}
+
+// Parses a comma separated list of expressions up to the end token
+func (par *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	list := []ast.Expression{}
+
+	if par.peekTokenIs(end) {
+		par.advanceTokens()
+		return list
+	}
+
 	par.advanceTokens()
-	args = append(args, par.parseExpression(LOWEST))
+	list = append(list, par.parseExpression(LOWEST))
 
 	for par.peekTokenIs(token.COMMA) {
 		par.advanceTokens()
 		par.advanceTokens()
-		args = append(args, par.parseExpression(LOWEST))
+		list = append(list, par.parseExpression(LOWEST))
 	}
 
-	if !par.peekAssertAdvance(token.RPAREN) {
+	if !par.peekAssertAdvance(end) {
 		return nil
 	}
 
-	return args
+	return list
+}
+
+func (par *Parser) parseSpreadExpression() ast.Expression {
+	exp := &ast.SpreadExpression{Token: par.curToken}
+	par.advanceTokens()
+	exp.Value = par.parseExpression(LOWEST)
+	return exp
+}
+
+func (par *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{Token: par.curToken}
+	array.Elements = par.parseExpressionList(token.RBRACKET)
+	return array
+}
+
+func (par *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	exp := &ast.IndexExpression{Token: par.curToken, Left: left}
+
+	par.advanceTokens()
+	exp.Index = par.parseExpression(LOWEST)
+
+	if !par.peekAssertAdvance(token.RBRACKET) {
+		return nil
+	}
+
+	return exp
+}
+
+func (par *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: par.curToken, Object: object}
+
+	if !par.peekAssertAdvance(token.IDENT) {
+		return nil
+	}
+	exp.Member = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+
+	return exp
+}
+
+// Parses a?.b and f?.(x)
+func (par *Parser) parseOptionalChain(left ast.Expression) ast.Expression {
+	if par.peekTokenIs(token.LPAREN) {
+		par.advanceTokens()
+		exp := par.parseCallExpression(left).(*ast.CallExpression)
+		exp.Optional = true
+		return exp
+	}
+
+	exp := par.parseMemberExpression(left)
+	if exp == nil {
+		return nil
+	}
+	exp.(*ast.MemberExpression).Optional = true
+	return exp
+}
+
+func (par *Parser) parseOptionalIndexExpression(left ast.Expression) ast.Expression {
+	exp := par.parseIndexExpression(left)
+	if exp == nil {
+		return nil
+	}
+	exp.(*ast.IndexExpression).Optional = true
+	return exp
+}
+
+func (par *Parser) parseCoalesceExpression(left ast.Expression) ast.Expression {
+	exp := &ast.CoalesceExpression{Token: par.curToken, Left: left}
+
+	par.advanceTokens()
+	exp.Right = par.parseExpression(COALESCE)
+
+	return exp
+}
+
+func (par *Parser) parseConditionalExpression(condition ast.Expression) ast.Expression {
+	exp := &ast.ConditionalExpression{Token: par.curToken, Condition: condition}
+
+	par.advanceTokens()
+	exp.Consequence = par.parseExpression(LOWEST)
+
+	if !par.peekAssertAdvance(token.COLON) {
+		return nil
+	}
+
+	// Parsing the alternative one level lower makes c ? a : d ? b : e nest to the right
+	par.advanceTokens()
+	exp.Alternative = par.parseExpression(TERNARY - 1)
+
+	return exp
+}
+
+func (par *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	member, ok := target.(*ast.MemberExpression)
+	if !ok || member.Optional {
+		msg := fmt.Sprintf("cannot assign to %s", target.String())
+		par.errors = append(par.errors, msg)
+		return nil
+	}
+
+	exp := &ast.AssignExpression{Token: par.curToken, Target: member}
+
+	// Parsing the value one level lower makes assignment right-associative
+	par.advanceTokens()
+	exp.Value = par.parseExpression(ASSIGN - 1)
+
+	return exp
+}
+
+func (par *Parser) parseStructStatement() ast.Statement {
+	stmt := &ast.StructStatement{Token: par.curToken}
+
+	if !par.peekAssertAdvance(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+
+	if !par.peekAssertAdvance(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Fields = []*ast.Identifier{}
+	for !par.peekTokenIs(token.RBRACE) {
+		if !par.peekAssertAdvance(token.IDENT) {
+			return nil
+		}
+		stmt.Fields = append(stmt.Fields, &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal})
+
+		if !par.peekTokenIs(token.RBRACE) && !par.peekAssertAdvance(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !par.peekAssertAdvance(token.RBRACE) {
+		return nil
+	}
+
+	if par.peekTokenIs(token.SEMICOLON) {
+		par.advanceTokens()
+	}
+
+	return stmt
+}
+
+func (par *Parser) parseClassStatement() ast.Statement {
+	stmt := &ast.ClassStatement{Token: par.curToken}
+
+	if !par.peekAssertAdvance(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+
+	if par.peekTokenIs(token.LT) {
+		par.advanceTokens()
+		if !par.peekAssertAdvance(token.IDENT) {
+			return nil
+		}
+		stmt.Parent = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+	}
+
+	if !par.peekAssertAdvance(token.LBRACE) {
+		return nil
+	}
+
+	for !par.peekTokenIs(token.RBRACE) {
+		method := par.parseMethodDefinition()
+		if method == nil {
+			return nil
+		}
+		stmt.Methods = append(stmt.Methods, method)
+	}
+
+	if !par.peekAssertAdvance(token.RBRACE) {
+		return nil
+	}
+
+	if par.peekTokenIs(token.SEMICOLON) {
+		par.advanceTokens()
+	}
+
+	return stmt
+}
+
+func (par *Parser) parseMethodDefinition() *ast.FunctionLiteral {
+	if !par.peekAssertAdvance(token.FUNCTION) {
+		return nil
+	}
+	method := &ast.FunctionLiteral{Token: par.curToken}
+	par.parseGeneratorMarker(method)
+
+	if !par.peekAssertAdvance(token.IDENT) {
+		return nil
+	}
+	method.Name = par.curToken.Literal
+
+	if !par.peekAssertAdvance(token.LPAREN) {
+		return nil
+	}
+
+	if !par.parseFunctionLiteralParameters(method) {
+		return nil
+	}
+
+	if len(method.Parameters) == 0 || method.Parameters[0].Value != "self" {
+		msg := fmt.Sprintf("method %s must take self as its first parameter", method.Name)
+		par.errors = append(par.errors, msg)
+		return nil
+	}
+
+	if !par.peekAssertAdvance(token.LBRACE) {
+		return nil
+	}
+
+	par.parseFunctionBody(method)
+
+	return method
+}
+
+func (par *Parser) parseStructLiteral(name *ast.Identifier) ast.Expression {
+	lit := &ast.StructLiteral{Token: par.curToken, Name: name}
+	par.advanceTokens()
+
+	for !par.peekTokenIs(token.RBRACE) {
+		if !par.peekAssertAdvance(token.IDENT) {
+			return nil
+		}
+		lit.Fields = append(lit.Fields, &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal})
+
+		if !par.peekAssertAdvance(token.COLON) {
+			return nil
+		}
+
+		par.advanceTokens()
+		lit.Values = append(lit.Values, par.parseExpression(LOWEST))
+
+		if !par.peekTokenIs(token.RBRACE) && !par.peekAssertAdvance(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !par.peekAssertAdvance(token.RBRACE) {
+		return nil
+	}
+
+	return lit
+}
+
+func (par *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: par.curToken}
+	hash.Pairs = make(map[ast.Expression]ast.Expression)
+
+	for !par.peekTokenIs(token.RBRACE) {
+		par.advanceTokens()
+		key := par.parseExpression(LOWEST)
+
+		if !par.peekAssertAdvance(token.COLON) {
+			return nil
+		}
+
+		par.advanceTokens()
+		value := par.parseExpression(LOWEST)
+
+		hash.Keys = append(hash.Keys, key)
+		hash.Pairs[key] = value
+
+		if !par.peekTokenIs(token.RBRACE) && !par.peekAssertAdvance(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !par.peekAssertAdvance(token.RBRACE) {
+		return nil
+	}
+
+	return hash
 }
 
 func (par *Parser) parseGroupedExpression() ast.Expression {
@@ -306,6 +926,29 @@ func (par *Parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+func (par *Parser) parseWhileExpression() ast.Expression {
+	expression := &ast.WhileExpression{Token: par.curToken}
+
+	if !par.peekAssertAdvance(token.LPAREN) {
+		return nil
+	}
+
+	par.advanceTokens()
+	expression.Condition = par.parseExpression(LOWEST)
+
+	if !par.peekAssertAdvance(token.RPAREN) {
+		return nil
+	}
+
+	if !par.peekAssertAdvance(token.LBRACE) {
+		return nil
+	}
+
+	expression.Body = par.parseBlockStatement()
+
+	return expression
+}
+
 func (par *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := ast.BlockStatement{Token: par.curToken}
 	block.Statements = []ast.Statement{}
@@ -325,22 +968,130 @@ func (par *Parser) parseBlockStatement() *ast.BlockStatement {
 
 func (par *Parser) parseFunctionLiteral() ast.Expression {
 	fl := &ast.FunctionLiteral{Token: par.curToken}
+	par.parseGeneratorMarker(fl)
 
 	if !par.peekAssertAdvance(token.LPAREN) {
 		return nil
 	}
 
-	fl.Parameters = par.parseFunctionParameters()
+	if !par.parseFunctionLiteralParameters(fl) {
+		return nil
+	}
 
 	if !par.peekAssertAdvance(token.LBRACE) {
 		return nil
 	}
 
-	fl.Body = par.parseBlockStatement()
+	par.parseFunctionBody(fl)
 
 	return fl
 }
 
+// Marks fl as a generator when fn is followed by a star: fn*(n) { ... }
+func (par *Parser) parseGeneratorMarker(fl *ast.FunctionLiteral) {
+	if par.peekTokenIs(token.ASTERISK) {
+		par.advanceTokens()
+		fl.Generator = true
+	}
+}
+
+// Parses the body of fl, allowing yield in it only if fl is a generator
+func (par *Parser) parseFunctionBody(fl *ast.FunctionLiteral) {
+	outer := par.inGenerator
+	par.inGenerator = fl.Generator
+	fl.Body = par.parseBlockStatement()
+	par.inGenerator = outer
+}
+
+func (par *Parser) parseYieldExpression() ast.Expression {
+	exp := &ast.YieldExpression{Token: par.curToken}
+
+	if !par.inGenerator {
+		par.errors = append(par.errors, "yield outside of a generator function")
+		return nil
+	}
+
+	if par.peekTokenIs(token.SEMICOLON) || par.peekTokenIs(token.RBRACE) {
+		return exp
+	}
+
+	par.advanceTokens()
+	exp.Value = par.parseExpression(LOWEST)
+
+	return exp
+}
+
+// Parses the parameters of fl. Each is a name or a pattern destructuring its argument
+// and may have a default value, and the last may be a rest parameter: fn(a, [b, c], d = 1, ...e)
+func (par *Parser) parseFunctionLiteralParameters(fl *ast.FunctionLiteral) bool {
+	fl.Parameters = []*ast.Identifier{}
+	patterns := []ast.Pattern{}
+	defaults := []ast.Expression{}
+	hasDefaults := false
+
+	// Nothing can follow the rest parameter
+	for !par.peekTokenIs(token.RPAREN) && fl.Rest == nil {
+		par.advanceTokens()
+
+		switch par.curToken.Type {
+		case token.IDENT:
+			fl.Parameters = append(fl.Parameters, &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal})
+			patterns = append(patterns, nil)
+		case token.LBRACKET, token.LBRACE:
+			tok := par.curToken
+			pattern := par.parsePattern()
+			if pattern == nil {
+				return false
+			}
+
+			// The parameter is named after its pattern, which no identifier can clash with
+			fl.Parameters = append(fl.Parameters, &ast.Identifier{Token: tok, Value: pattern.String()})
+			patterns = append(patterns, pattern)
+		case token.ELLIPSIS:
+			if !par.peekAssertAdvance(token.IDENT) {
+				return false
+			}
+			fl.Rest = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+			continue
+		default:
+			msg := fmt.Sprintf("expected a parameter, got %s", par.curToken.Type)
+			par.errors = append(par.errors, msg)
+			return false
+		}
+
+		param := fl.Parameters[len(fl.Parameters)-1]
+		if par.peekTokenIs(token.ASSIGN) {
+			par.advanceTokens()
+			par.advanceTokens()
+			defaults = append(defaults, par.parseExpression(LOWEST))
+			hasDefaults = true
+		} else if hasDefaults {
+			msg := fmt.Sprintf("parameter %s without a default follows a parameter with one", param.Value)
+			par.errors = append(par.errors, msg)
+			return false
+		} else {
+			defaults = append(defaults, nil)
+		}
+
+		if !par.peekTokenIs(token.RPAREN) && !par.peekAssertAdvance(token.COMMA) {
+			return false
+		}
+	}
+
+	// The per-parameter slices are only kept when something is in them
+	for _, pattern := range patterns {
+		if pattern != nil {
+			fl.Patterns = patterns
+			break
+		}
+	}
+	if hasDefaults {
+		fl.Defaults = defaults
+	}
+
+	return par.peekAssertAdvance(token.RPAREN)
+}
+
 func (par *Parser) parseFunctionParameters() []*ast.Identifier {
 	identifiers := []*ast.Identifier{}
 
@@ -401,8 +1152,15 @@ func (par *Parser) peekPrecedence() int {
 	return LOWEST
 }
 
+// The hint given when a ? written straight after an operand, which propagates errors,
+// is followed by an expression as if it were a conditional
+const misspacedConditional = "a conditional ? needs a space before it, as in `c ? a : b`"
+
 func (par *Parser) appendNextTokenError(t token.TokenType) {
 	msg := fmt.Sprintf("expected next token to be %s, but got %s instead", t, par.peekToken.Type)
+	if par.curTokenIs(token.QUESTION) && par.prefixParseFns[par.peekToken.Type] != nil {
+		msg += "; " + misspacedConditional
+	}
 	par.errors = append(par.errors, msg)
 }
 
@@ -155,6 +155,88 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestBigIntegerLiteralExpression(t *testing.T) {
+	input := `99999999999999999999;`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program has not enough statements. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	literal, ok := stmt.Expression.(*ast.BigIntegerLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.BigIntegerLiteral. got=%T", stmt.Expression)
+	}
+	if literal.Value.String() != "99999999999999999999" {
+		t.Errorf("literal.Value not %s. got=%s", "99999999999999999999", literal.Value)
+	}
+	if literal.TokenLiteral() != "99999999999999999999" {
+		t.Errorf("literal.TokenLiteral not %s. got=%s", "99999999999999999999", literal.TokenLiteral())
+	}
+}
+
+func TestDecimalLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedValue int64
+		expectedScale int
+	}{
+		{"12.50d", 1250, 2},
+		{"0.001d", 1, 3},
+		{"7d", 7, 0},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.DecimalLiteral)
+		if !ok {
+			t.Fatalf("exp not *ast.DecimalLiteral. got=%T", stmt.Expression)
+		}
+		if literal.Value.Int64() != tt.expectedValue {
+			t.Errorf("literal.Value not %d. got=%s", tt.expectedValue, literal.Value)
+		}
+		if literal.Scale != tt.expectedScale {
+			t.Errorf("literal.Scale not %d. got=%d", tt.expectedScale, literal.Scale)
+		}
+		if literal.TokenLiteral() != tt.input {
+			t.Errorf("literal.TokenLiteral not %s. got=%s", tt.input, literal.TokenLiteral())
+		}
+	}
+}
+
+func TestStringLiteralExpression(t *testing.T) {
+	input := `"hello world";`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stmt.Expression.(*ast.StringLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.StringLiteral. got=%T", stmt.Expression)
+	}
+
+	if literal.Value != "hello world" {
+		t.Errorf("literal.Value not %q. got=%q", "hello world", literal.Value)
+	}
+}
+
 func TestBooleanExpression(t *testing.T) {
 	tests := []struct {
 		input           string
@@ -315,6 +397,61 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		{"2 / (5 + 5)", "(2 / (5 + 5))"},
 		{"-(5 + 5)", "(-(5 + 5))"},
 		{"!(true == true)", "(!(true == true))"},
+		{"a * [1, 2, 3, 4][b * c] * d", "((a * ([1, 2, 3, 4][(b * c)])) * d)"},
+		{"add(a * b[2], b[1], 2 * [1, 2][1])", "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))"},
+		{"a.b", "(a.b)"},
+		{"a.b.c", "((a.b).c)"},
+		{"a.b(c, d)", "(a.b)(c, d)"},
+		{"a.b(c).d()", "((a.b)(c).d)()"},
+		{"-a.b", "(-(a.b))"},
+		{"a.b[1]", "((a.b)[1])"},
+		{"a[1].b", "((a[1]).b)"},
+		{"a.b + c.d * 2", "((a.b) + ((c.d) * 2))"},
+		{"p.x = p.y + 1", "((p.x) = ((p.y) + 1))"},
+		{"x |> f", "(x |> f)"},
+		{"x |> f(a)", "(x |> f(a))"},
+		{"x |> f |> g(b)", "((x |> f) |> g(b))"},
+		{"a + b |> f", "((a + b) |> f)"},
+		{"a == b |> f", "((a == b) |> f)"},
+		{"x |> a.b(c)", "(x |> (a.b)(c))"},
+		{"f >> g", "(f >> g)"},
+		{"f >> g >> h", "((f >> g) >> h)"},
+		{"x |> f >> g", "(x |> (f >> g))"},
+		{"f >> g == h", "(f >> (g == h))"},
+		{"f >> g(1)", "(f >> g(1))"},
+		{"p.x = a |> f", "((p.x) = (a |> f))"},
+		{"1..10", "(1..10)"},
+		{"a + 1..=b * 2", "((a + 1)..=(b * 2))"},
+		{"0..n step 2", "(0..n step 2)"},
+		{"arr[..5]", "(arr[(..5)])"},
+		{"arr[2..]", "(arr[(2..)])"},
+		{"(1..).take(3)", "((1..).take)(3)"},
+		{"f(1.., ..2)", "f((1..), (..2))"},
+		{"x < 1..3", "(x < (1..3))"},
+		{"f(x)?", "(f(x)?)"},
+		{"a + f(x)? * 2", "(a + ((f(x)?) * 2))"},
+		{"(f(x)?).b", "((f(x)?).b)"},
+		{"a?.b.c", "((a?.b).c)"},
+		{"a?.b(c)", "(a?.b)(c)"},
+		{"f?.(x)", "f?.(x)"},
+		{"a?[i].b", "((a?[i]).b)"},
+		{"a ?? b ?? c", "((a ?? b) ?? c)"},
+		{"c ? a : b", "(c ? a : b)"},
+		{"x > 1 ? a + 1 : -b", "((x > 1) ? (a + 1) : (-b))"},
+		{"a ? b : c ? d : e", "(a ? b : (c ? d : e))"},
+		{"a ? b ? c : d : e", "(a ? (b ? c : d) : e)"},
+		{"a ?? b ? c : d", "((a ?? b) ? c : d)"},
+		{"x |> ok ? a : b", "((x |> ok) ? a : b)"},
+		{"p.x = c ? [1] : [2]", "((p.x) = (c ? [1] : [2]))"},
+		{"f(x)? ? a : b", "((f(x)?) ? a : b)"},
+		{"f(x)?\ng(y)", "(f(x)?)g(y)"},
+		{"a.b ?? c + d", "((a.b) ?? (c + d))"},
+		{"a ?? b == c", "(a ?? (b == c))"},
+		{"x |> f ?? g", "(x |> (f ?? g))"},
+		{"for (x in 1..3) { x }", "for (x in (1..3)) x"},
+		{"for ([k, v] in pairs) { k }", "for ([k, v] in pairs) k"},
+		{"a.x = b.y = 2", "((a.x) = ((b.y) = 2))"},
+		{"Point{x: 1, y: a + b}.x", "(Point{x: 1, y: (a + b)}.x)"},
 	}
 
 	for _, tt := range tests {
@@ -433,6 +570,46 @@ func TestIfElseExpression(t *testing.T) {
 	}
 }
 
+func TestWhileExpression(t *testing.T) {
+	input := `while (x < y) { x }`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n", 1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.WhileExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.WhileExpression. got=%T", stmt.Expression)
+	}
+
+	if !testInfixExpression(t, exp.Condition, "x", "<", "y") {
+		return
+	}
+
+	if len(exp.Body.Statements) != 1 {
+		t.Errorf("body is not 1 statements. got=%d\n", len(exp.Body.Statements))
+	}
+
+	body, ok := exp.Body.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("Statements[0] is not ast.ExpressionStatement. got=%T", exp.Body.Statements[0])
+	}
+
+	if !testIdentifier(t, body.Expression, "x") {
+		return
+	}
+}
+
 func TestFunctionLiteral(t *testing.T) {
 	input := `fn(x, y) {x + y}`
 
@@ -603,6 +780,659 @@ func TestCallExpressionParameterParsing(t *testing.T) {
 	}
 }
 
+func TestParsingArrayLiterals(t *testing.T) {
+	input := "[1, 2 * 2, 3 + 3]"
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("exp not ast.ArrayLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(array.Elements) != 3 {
+		t.Fatalf("len(array.Elements) not 3. got=%d", len(array.Elements))
+	}
+
+	testIntegerLiteral(t, array.Elements[0], 1)
+	testInfixExpression(t, array.Elements[1], 2, "*", 2)
+	testInfixExpression(t, array.Elements[2], 3, "+", 3)
+}
+
+func TestParsingIndexExpressions(t *testing.T) {
+	input := "myArray[1 + 1]"
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+	indexExp, ok := stmt.Expression.(*ast.IndexExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.IndexExpression. got=%T", stmt.Expression)
+	}
+
+	if !testIdentifier(t, indexExp.Left, "myArray") {
+		return
+	}
+
+	if !testInfixExpression(t, indexExp.Index, 1, "+", 1) {
+		return
+	}
+}
+
+func TestParsingHashLiterals(t *testing.T) {
+	input := `{"one": 1, "two": 2, "three": 3}`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	expected := []struct {
+		key   string
+		value int64
+	}{{"one", 1}, {"two", 2}, {"three", 3}}
+
+	if len(hash.Keys) != len(expected) {
+		t.Fatalf("hash.Keys has wrong length. got=%d", len(hash.Keys))
+	}
+
+	for i, key := range hash.Keys {
+		literal, ok := key.(*ast.StringLiteral)
+		if !ok {
+			t.Errorf("key is not ast.StringLiteral. got=%T", key)
+			continue
+		}
+		if literal.Value != expected[i].key {
+			t.Errorf("key %d is not %q. got=%q", i, expected[i].key, literal.Value)
+		}
+		testIntegerLiteral(t, hash.Pairs[key], expected[i].value)
+	}
+}
+
+func TestParsingEmptyHashLiteral(t *testing.T) {
+	input := "{}"
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(hash.Pairs) != 0 {
+		t.Errorf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+}
+
+func TestMemberExpressionErrors(t *testing.T) {
+	lex := lexer.New("a.1")
+	par := New(lex)
+	par.ParseProgram()
+
+	errors := par.Errors()
+	if len(errors) == 0 || errors[0] != "expected next token to be IDENT, but got INT instead" {
+		t.Errorf("expected an error for a non-identifier member. got=%v", errors)
+	}
+}
+
+func TestStructStatement(t *testing.T) {
+	input := `struct Point { x, y }`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.StructStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.StructStatement. got=%T", program.Statements[0])
+	}
+
+	if stmt.Name.Value != "Point" {
+		t.Errorf("stmt.Name.Value not 'Point'. got=%q", stmt.Name.Value)
+	}
+
+	if len(stmt.Fields) != 2 || stmt.Fields[0].Value != "x" || stmt.Fields[1].Value != "y" {
+		t.Errorf("stmt.Fields wrong. got=%v", stmt.Fields)
+	}
+}
+
+func TestParsingStructLiterals(t *testing.T) {
+	input := `Point{x: 1, y: 2 * 3}`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	lit, ok := stmt.Expression.(*ast.StructLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.StructLiteral. got=%T", stmt.Expression)
+	}
+
+	if lit.Name.Value != "Point" {
+		t.Errorf("lit.Name.Value not 'Point'. got=%q", lit.Name.Value)
+	}
+
+	if len(lit.Fields) != 2 {
+		t.Fatalf("lit.Fields has wrong length. got=%d", len(lit.Fields))
+	}
+
+	testIntegerLiteral(t, lit.Values[0], 1)
+	testInfixExpression(t, lit.Values[1], 2, "*", 3)
+}
+
+func TestClassStatement(t *testing.T) {
+	input := `class Loud < Counter {
+		fn inc(self) { self.count + 1 }
+		fn add(self, n) { self.count + n }
+	}`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	stmt, ok := program.Statements[0].(*ast.ClassStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.ClassStatement. got=%T", program.Statements[0])
+	}
+
+	if stmt.Name.Value != "Loud" {
+		t.Errorf("stmt.Name.Value not 'Loud'. got=%q", stmt.Name.Value)
+	}
+
+	if stmt.Parent == nil || stmt.Parent.Value != "Counter" {
+		t.Errorf("stmt.Parent not 'Counter'. got=%v", stmt.Parent)
+	}
+
+	if len(stmt.Methods) != 2 {
+		t.Fatalf("stmt.Methods does not contain 2 methods. got=%d", len(stmt.Methods))
+	}
+
+	if stmt.Methods[1].Name != "add" || len(stmt.Methods[1].Parameters) != 2 {
+		t.Errorf("second method wrong. got=%s", stmt.Methods[1].String())
+	}
+}
+
+func TestClassStatementErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"class A { fn f() { 1 } }", "method f must take self as its first parameter"},
+		{"class A { fn f(x) { 1 } }", "method f must take self as its first parameter"},
+		{"class A { let x = 1; }", "expected next token to be FUNCTION, but got LET instead"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected error %q. got=%v", tt.expected, errors)
+		}
+	}
+}
+
+func TestEnumStatement(t *testing.T) {
+	input := `enum Shape { Circle(r), Rect(w, h), Empty }`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	stmt, ok := program.Statements[0].(*ast.EnumStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.EnumStatement. got=%T", program.Statements[0])
+	}
+
+	if stmt.String() != input {
+		t.Errorf("stmt.String() wrong. expected=%q, got=%q", input, stmt.String())
+	}
+
+	if len(stmt.Variants) != 3 || len(stmt.Variants[1].Fields) != 2 || stmt.Variants[2].Fields != nil {
+		t.Errorf("stmt.Variants wrong. got=%v", stmt.Variants)
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match (x) { 1 -> a, _ -> b }`, "match x { 1 -> a; _ -> b; }"},
+		{`match (x) { -1 -> a }`, "match x { (-1) -> a; }"},
+		{`match (s) { Circle(r) if r > 1 -> r }`, "match s { Circle(r) if (r > 1) -> r; }"},
+		{`match (s) { Shape.Rect(w, _) -> w; Empty -> 0 }`, "match s { Shape.Rect(w, _) -> w; Empty -> 0; }"},
+		{`match (a) { [x, [y], ...rest] -> { x } }`, "match a { [x, [y], ...rest] -> x; }"},
+		{`match (h) { {"name": n, age} -> n }`, "match h { {name: n, age: age} -> n; }"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q. got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestSwitchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`switch (x) { case 1, 2 -> a; default -> b }`, "switch x { case 1, 2 -> a; default -> b; }"},
+		{`switch (x) { case "a" -> { 1 }, case y + 1 -> 2 }`, "switch x { case a -> 1; case (y + 1) -> 2; }"},
+		{`switch (x) { default -> 0 }`, "switch x { default -> 0; }"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q. got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestSwitchErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`switch (x) { 1 -> a }`, "expected case or default, got 1"},
+		{`switch (x) { case 1: a }`, "expected next token to be ->, but got : instead"},
+		{`switch (x) { default -> a; default -> b }`, "switch has more than one default"},
+		{`c ? a`, "expected next token to be :, but got EOF instead"},
+		{`c? a : b`, "a conditional ? needs a space before it, as in `c ? a : b`"},
+		{`let x = n > 1? "many" : "one"`, "a conditional ? needs a space before it, as in `c ? a : b`"},
+		{`puts(c? a : b)`, "expected next token to be ,, but got IDENT instead; a conditional ? needs a space before it, as in `c ? a : b`"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected error %q. got=%v", tt.expected, errors)
+		}
+	}
+}
+
+func TestPatternErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match (x) { x + 1 -> x }`, "expected next token to be ->, but got + instead"},
+		{`match (x) { fn -> x }`, "expected a pattern, got FUNCTION"},
+		{`match (x) { -a -> x }`, "expected a pattern, got IDENT"},
+		{`match (x) { [...rest, a] -> x }`, "expected next token to be ], but got , instead"},
+		{`match (x) { {1: a} -> x }`, "expected a pattern, got INT"},
+		{`enum Shape { circle }`, "enum variant circle must start with an uppercase letter"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected error %q. got=%v", tt.expected, errors)
+		}
+	}
+}
+
+func TestDestructuringParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let [a, b, ...rest] = arr;`, "let [a, b, ...rest] = arr;"},
+		{`let {name, "age": a} = person;`, "let {name: name, age: a} = person;"},
+		{`fn([a, b], c) { a }`, "fn([a, b], c)a"},
+		{`fn(x, {name}) { name }`, "fn(x, {name: name})name"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q. got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestDestructuringPatterns(t *testing.T) {
+	lex := lexer.New(`fn(x, [a, b]) { a }`)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	function := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if len(function.Patterns) != 2 {
+		t.Fatalf("function.Patterns does not contain 2 entries. got=%d", len(function.Patterns))
+	}
+
+	if function.Patterns[0] != nil {
+		t.Errorf("function.Patterns[0] is not nil. got=%T", function.Patterns[0])
+	}
+
+	if _, ok := function.Patterns[1].(*ast.ArrayPattern); !ok {
+		t.Errorf("function.Patterns[1] is not *ast.ArrayPattern. got=%T", function.Patterns[1])
+	}
+
+	lex = lexer.New(`fn(x, y) { x }`)
+	par = New(lex)
+	program = par.ParseProgram()
+	checkParserErrors(t, par)
+
+	function = program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if function.Patterns != nil {
+		t.Errorf("function.Patterns is not nil. got=%v", function.Patterns)
+	}
+}
+
+func TestParameterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`fn(1) { 1 }`, "expected a parameter, got INT"},
+		{`fn(a b) { 1 }`, "expected next token to be ,, but got IDENT instead"},
+		{`let 1 = 2;`, "expected next token to be IDENT, but got INT instead"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected error %q. got=%v", tt.expected, errors)
+		}
+	}
+}
+
+func TestFlexibleParameterParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`fn(x, y = 10) { x }`, "fn(x, y = 10)x"},
+		{`fn(first, ...others) { first }`, "fn(first, ...others)first"},
+		{`fn(...all) { all }`, "fn(...all)all"},
+		{`fn([a, b] = [1, 2], c = a + b) { c }`, "fn([a, b] = [1, 2], c = (a + b))c"},
+		{`f(...arr)`, "f(...arr)"},
+		{`f(1, ...a, ...b.c)`, "f(1, ...a, ...(b.c))"},
+		{`f(1, y: 2, z: a + b)`, "f(1, y: 2, z: (a + b))"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q. got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestFlexibleParameterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`fn(x = 1, y) { x }`, "parameter y without a default follows a parameter with one"},
+		{`fn(...a, b) { a }`, "expected next token to be ), but got , instead"},
+		{`fn(...1) { 1 }`, "expected next token to be IDENT, but got INT instead"},
+		{`f(y: 1, 2)`, "positional argument follows named argument"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected error %q. got=%v", tt.expected, errors)
+		}
+	}
+}
+
+func TestRangeAndForErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`arr[1..=]`, "inclusive range must have an end"},
+		{`for x in xs { x }`, "expected next token to be (, but got IDENT instead"},
+		{`for (x of xs) { x }`, "expected next token to be IN, but got IDENT instead"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected error %q. got=%v", tt.expected, errors)
+		}
+	}
+}
+
+func TestGeneratorParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`fn*(n) { yield n * 2 }`, "fn*(n)yield (n * 2)"},
+		{`fn*() { yield; }`, "fn*()yield"},
+		{`fn*() { let x = yield 1; }`, "fn*()let x = yield 1;"},
+		{`class Bag { fn* items(self) { yield self } }`, "class Bag { fn* items(self)yield self }"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q. got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestYieldOutsideGenerator(t *testing.T) {
+	tests := []string{
+		`yield 1`,
+		`fn() { yield 1 }`,
+		`fn*() { fn() { yield 1 } }`,
+	}
+
+	for _, input := range tests {
+		lex := lexer.New(input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0] != "yield outside of a generator function" {
+			t.Errorf("expected an error for yield in %q. got=%v", input, errors)
+		}
+	}
+}
+
+func TestTryParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { f(x) } catch (e) { e.message }`, "try f(x) catch (e) (e.message)"},
+		{`try { f(x) } catch { 0 }`, "try f(x) catch 0"},
+		{`try { f(x) } finally { close() }`, "try f(x) finally close()"},
+		{`try { f(x) } catch (e) { throw e } finally { close() }`, "try f(x) catch (e) throw e finally close()"},
+		{`throw "bad " + name`, "throw (bad  + name)"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q. got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestTryErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { f(x) }`, "try without catch or finally"},
+		{`try { f(x) } catch (1) { 0 }`, "expected next token to be IDENT, but got INT instead"},
+		{`try f(x) catch { 0 }`, "expected next token to be {, but got IDENT instead"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected error %q. got=%v", tt.expected, errors)
+		}
+	}
+}
+
+func TestTemplateParsing(t *testing.T) {
+	input := `"${n} items cost ${total(items) * 2}"`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	template, ok := stmt.Expression.(*ast.TemplateLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.TemplateLiteral. got=%T", stmt.Expression)
+	}
+
+	expected := []string{"n", " items cost ", "(total(items) * 2)"}
+	if len(template.Parts) != len(expected) {
+		t.Fatalf("wrong number of parts. expected=%d, got=%d", len(expected), len(template.Parts))
+	}
+	for i, part := range template.Parts {
+		if part.String() != expected[i] {
+			t.Errorf("part %d wrong. expected=%q, got=%q", i, expected[i], part.String())
+		}
+	}
+
+	if template.String() != "${n} items cost ${(total(items) * 2)}" {
+		t.Errorf("template.String() wrong. got=%q", template.String())
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a ${b"`, "unterminated ${ in string"},
+		{`"a ${}"`, "empty ${} in string"},
+		{`"a ${b c}"`, "unexpected c in ${b c}"},
+		{`"a ${1 +}"`, "no prefix parse function for EOF found"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected error %q. got=%v", tt.expected, errors)
+		}
+	}
+}
+
+func TestAssignExpressionErrors(t *testing.T) {
+	lex := lexer.New("a = 1")
+	par := New(lex)
+	par.ParseProgram()
+
+	errors := par.Errors()
+	if len(errors) == 0 || errors[0] != "cannot assign to a" {
+		t.Errorf("expected an error for assigning to an identifier. got=%v", errors)
+	}
+}
+
+func TestOptionalAssignmentError(t *testing.T) {
+	lex := lexer.New("a?.b = 1")
+	par := New(lex)
+	par.ParseProgram()
+
+	errors := par.Errors()
+	if len(errors) == 0 || errors[0] != "cannot assign to (a?.b)" {
+		t.Errorf("expected an error for assigning to an optional member. got=%v", errors)
+	}
+}
+
 func checkParserErrors(t *testing.T, par *Parser) {
 	errors := par.Errors()
 	if len(errors) == 0 {
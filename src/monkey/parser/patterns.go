@@ -0,0 +1,341 @@
+package parser
+
+import (
+	"fmt"
+	"monkey/ast"
+	"monkey/token"
+)
+
+// ===================
+// Enums and Matching
+// ===================
+func (par *Parser) parseEnumStatement() ast.Statement {
+	stmt := &ast.EnumStatement{Token: par.curToken}
+
+	if !par.peekAssertAdvance(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+
+	if !par.peekAssertAdvance(token.LBRACE) {
+		return nil
+	}
+
+	for !par.peekTokenIs(token.RBRACE) {
+		if !par.peekAssertAdvance(token.IDENT) {
+			return nil
+		}
+		variant := &ast.EnumVariant{Name: &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}}
+
+		// Patterns tell variants from bindings by their capital letter
+		if !isStructName(variant.Name.Value) {
+			msg := fmt.Sprintf("enum variant %s must start with an uppercase letter", variant.Name.Value)
+			par.errors = append(par.errors, msg)
+			return nil
+		}
+
+		if par.peekTokenIs(token.LPAREN) {
+			par.advanceTokens()
+			variant.Fields = par.parseFunctionParameters()
+			if variant.Fields == nil {
+				return nil
+			}
+		}
+		stmt.Variants = append(stmt.Variants, variant)
+
+		if !par.peekTokenIs(token.RBRACE) && !par.peekAssertAdvance(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !par.peekAssertAdvance(token.RBRACE) {
+		return nil
+	}
+
+	if par.peekTokenIs(token.SEMICOLON) {
+		par.advanceTokens()
+	}
+
+	return stmt
+}
+
+func (par *Parser) parseMatchExpression() ast.Expression {
+	exp := &ast.MatchExpression{Token: par.curToken}
+
+	if !par.peekAssertAdvance(token.LPAREN) {
+		return nil
+	}
+
+	par.advanceTokens()
+	exp.Subject = par.parseExpression(LOWEST)
+
+	if !par.peekAssertAdvance(token.RPAREN) {
+		return nil
+	}
+
+	if !par.peekAssertAdvance(token.LBRACE) {
+		return nil
+	}
+
+	for !par.peekTokenIs(token.RBRACE) {
+		par.advanceTokens()
+
+		arm := &ast.MatchArm{Pattern: par.parsePattern()}
+		if arm.Pattern == nil {
+			return nil
+		}
+
+		if par.peekTokenIs(token.IF) {
+			par.advanceTokens()
+			par.advanceTokens()
+			arm.Guard = par.parseExpression(LOWEST)
+		}
+
+		if !par.peekAssertAdvance(token.ARROW) {
+			return nil
+		}
+
+		arm.Body = par.parseArmBody()
+		exp.Arms = append(exp.Arms, arm)
+
+		if par.peekTokenIs(token.COMMA) || par.peekTokenIs(token.SEMICOLON) {
+			par.advanceTokens()
+		}
+	}
+
+	if !par.peekAssertAdvance(token.RBRACE) {
+		return nil
+	}
+
+	return exp
+}
+
+// Parses switch (x) { case 1, 2 -> a; default -> b }, with the same arm bodies as match
+func (par *Parser) parseSwitchExpression() ast.Expression {
+	exp := &ast.SwitchExpression{Token: par.curToken}
+
+	if !par.peekAssertAdvance(token.LPAREN) {
+		return nil
+	}
+
+	par.advanceTokens()
+	exp.Subject = par.parseExpression(LOWEST)
+
+	if !par.peekAssertAdvance(token.RPAREN) {
+		return nil
+	}
+
+	if !par.peekAssertAdvance(token.LBRACE) {
+		return nil
+	}
+
+	for !par.peekTokenIs(token.RBRACE) {
+		par.advanceTokens()
+
+		switch par.curToken.Type {
+		case token.CASE:
+			c := &ast.SwitchCase{}
+			for {
+				par.advanceTokens()
+				c.Values = append(c.Values, par.parseExpression(LOWEST))
+				if !par.peekTokenIs(token.COMMA) {
+					break
+				}
+				par.advanceTokens()
+			}
+
+			if !par.peekAssertAdvance(token.ARROW) {
+				return nil
+			}
+			c.Body = par.parseArmBody()
+			exp.Cases = append(exp.Cases, c)
+		case token.DEFAULT:
+			if exp.Default != nil {
+				par.errors = append(par.errors, "switch has more than one default")
+				return nil
+			}
+			if !par.peekAssertAdvance(token.ARROW) {
+				return nil
+			}
+			exp.Default = par.parseArmBody()
+		default:
+			msg := fmt.Sprintf("expected case or default, got %s", par.curToken.Literal)
+			par.errors = append(par.errors, msg)
+			return nil
+		}
+
+		if par.peekTokenIs(token.COMMA) || par.peekTokenIs(token.SEMICOLON) {
+			par.advanceTokens()
+		}
+	}
+
+	if !par.peekAssertAdvance(token.RBRACE) {
+		return nil
+	}
+
+	return exp
+}
+
+// Parses the body following an arrow, which is either a block or a single expression
+func (par *Parser) parseArmBody() *ast.BlockStatement {
+	if par.peekTokenIs(token.LBRACE) {
+		par.advanceTokens()
+		return par.parseBlockStatement()
+	}
+
+	par.advanceTokens()
+	stmt := &ast.ExpressionStatement{Token: par.curToken}
+	stmt.Expression = par.parseExpression(LOWEST)
+
+	return &ast.BlockStatement{Token: stmt.Token, Statements: []ast.Statement{stmt}}
+}
+
+// ===================
+// Patterns
+// ===================
+func (par *Parser) parsePattern() ast.Pattern {
+	switch par.curToken.Type {
+	case token.IDENT:
+		ident := &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+		switch {
+		case ident.Value == "_":
+			return &ast.WildcardPattern{Token: par.curToken}
+		case isStructName(ident.Value):
+			return par.parseVariantPattern(ident)
+		default:
+			return &ast.BindingPattern{Token: par.curToken, Name: ident}
+		}
+	case token.INT, token.DECIMAL, token.STRING, token.TRUE, token.FALSE:
+		return &ast.LiteralPattern{Token: par.curToken, Value: par.prefixParseFns[par.curToken.Type]()}
+	case token.MINUS:
+		if !par.peekTokenIs(token.INT) && !par.peekTokenIs(token.DECIMAL) {
+			par.patternError(par.peekToken)
+			return nil
+		}
+		return &ast.LiteralPattern{Token: par.curToken, Value: par.parsePrefixExpression()}
+	case token.LBRACKET:
+		return par.parseArrayPattern()
+	case token.LBRACE:
+		return par.parseHashPattern()
+	default:
+		par.patternError(par.curToken)
+		return nil
+	}
+}
+
+func (par *Parser) parseVariantPattern(name *ast.Identifier) ast.Pattern {
+	pattern := &ast.VariantPattern{Token: par.curToken, Name: name}
+
+	if par.peekTokenIs(token.DOT) {
+		par.advanceTokens()
+		if !par.peekAssertAdvance(token.IDENT) {
+			return nil
+		}
+		pattern.Enum = name
+		pattern.Name = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+	}
+
+	if !par.peekTokenIs(token.LPAREN) {
+		return pattern
+	}
+	par.advanceTokens()
+
+	pattern.Arguments = []ast.Pattern{}
+	for !par.peekTokenIs(token.RPAREN) {
+		par.advanceTokens()
+
+		arg := par.parsePattern()
+		if arg == nil {
+			return nil
+		}
+		pattern.Arguments = append(pattern.Arguments, arg)
+
+		if !par.peekTokenIs(token.RPAREN) && !par.peekAssertAdvance(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !par.peekAssertAdvance(token.RPAREN) {
+		return nil
+	}
+
+	return pattern
+}
+
+func (par *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: par.curToken}
+
+	for !par.peekTokenIs(token.RBRACKET) {
+		par.advanceTokens()
+
+		// The rest of the array can only be collected by the last element
+		if par.curTokenIs(token.ELLIPSIS) {
+			if !par.peekAssertAdvance(token.IDENT) {
+				return nil
+			}
+			pattern.Rest = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+			break
+		}
+
+		element := par.parsePattern()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !par.peekTokenIs(token.RBRACKET) && !par.peekAssertAdvance(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !par.peekAssertAdvance(token.RBRACKET) {
+		return nil
+	}
+
+	return pattern
+}
+
+func (par *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: par.curToken}
+
+	for !par.peekTokenIs(token.RBRACE) {
+		par.advanceTokens()
+
+		if !par.curTokenIs(token.IDENT) && !par.curTokenIs(token.STRING) {
+			par.patternError(par.curToken)
+			return nil
+		}
+		key := par.curToken
+
+		// A bare name binds the value under the key of the same name
+		var value ast.Pattern
+		if key.Type == token.IDENT && !par.peekTokenIs(token.COLON) {
+			value = &ast.BindingPattern{Token: key, Name: &ast.Identifier{Token: key, Value: key.Literal}}
+		} else {
+			if !par.peekAssertAdvance(token.COLON) {
+				return nil
+			}
+			par.advanceTokens()
+			if value = par.parsePattern(); value == nil {
+				return nil
+			}
+		}
+		pattern.Keys = append(pattern.Keys, key.Literal)
+		pattern.Values = append(pattern.Values, value)
+
+		if !par.peekTokenIs(token.RBRACE) && !par.peekAssertAdvance(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !par.peekAssertAdvance(token.RBRACE) {
+		return nil
+	}
+
+	return pattern
+}
+
+func (par *Parser) patternError(tok token.Token) {
+	msg := fmt.Sprintf("expected a pattern, got %s", tok.Type)
+	par.errors = append(par.errors, msg)
+}
@@ -12,8 +12,12 @@ const (
 	EOF     = "EOF"
 
 	// Identifiers + Literals
-	IDENT = "IDENT"
-	INT   = "INT"
+	IDENT   = "IDENT"
+	INT     = "INT"
+	DECIMAL = "DECIMAL"
+	STRING  = "STRING"
+	// A string with ${...} interpolations. Its literal is the raw text between the quotes.
+	TEMPLATE = "TEMPLATE"
 
 	// Operators
 	ASSIGN   = "="
@@ -27,15 +31,33 @@ const (
 	GT     = ">"
 	EQ     = "=="
 	NOT_EQ = "!="
+	ARROW  = "->"
+
+	PIPE    = "|>"
+	COMPOSE = ">>"
+
+	QUESTION       = "?"       // directly after an operand: f(x)?
+	TERNARY        = "TERNARY" // the ? of cond ? a : b, which follows whitespace
+	OPTIONAL_DOT   = "?."
+	OPTIONAL_INDEX = "?["
+	COALESCE       = "??"
 
 	// Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
+	COLON     = ":"
+	DOT       = "."
+	ELLIPSIS  = "..."
+
+	RANGE           = ".."
+	RANGE_INCLUSIVE = "..="
 
-	LPAREN = "("
-	RPAREN = ")"
-	LBRACE = "{"
-	RBRACE = "}"
+	LPAREN   = "("
+	RPAREN   = ")"
+	LBRACE   = "{"
+	RBRACE   = "}"
+	LBRACKET = "["
+	RBRACKET = "]"
 
 	// Keywords
 	FUNCTION = "FUNCTION"
@@ -45,16 +67,46 @@ const (
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
+	WHILE    = "WHILE"
+	STRUCT   = "STRUCT"
+	CLASS    = "CLASS"
+	ENUM     = "ENUM"
+	MATCH    = "MATCH"
+	FOR      = "FOR"
+	IN       = "IN"
+	SWITCH   = "SWITCH"
+	CASE     = "CASE"
+	DEFAULT  = "DEFAULT"
+	YIELD    = "YIELD"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	THROW    = "THROW"
 )
 
 var keywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"fn":      FUNCTION,
+	"let":     LET,
+	"true":    TRUE,
+	"false":   FALSE,
+	"if":      IF,
+	"else":    ELSE,
+	"return":  RETURN,
+	"while":   WHILE,
+	"struct":  STRUCT,
+	"class":   CLASS,
+	"enum":    ENUM,
+	"match":   MATCH,
+	"for":     FOR,
+	"in":      IN,
+	"switch":  SWITCH,
+	"case":    CASE,
+	"default": DEFAULT,
+	"yield":   YIELD,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"throw":   THROW,
 }
 
 func LookupIdent(ident string) TokenType {